@@ -0,0 +1,101 @@
+package roids
+
+import (
+	"reflect"
+
+	"github.com/ShounakA/roids/core"
+)
+
+// Adds the constructor to the global container, using the type it returns as the specification.
+// See `RoidsContainer.Provide`.
+func Provide(ctor any, opts ...ServiceOption) error {
+	return GetRoids().Provide(ctor, opts...)
+}
+
+// Adds the constructor to the container, using the type it returns as the specification.
+// The service is static unless `WithLifetime` is used. Use `As` to also resolve the same instance
+// from interfaces, e.g. `Provide(NewCache, As[ICache](), As[io.Closer]())`.
+// Constructors returning several services, e.g. `(IReader, IWriter, error)`, add each of them
+// as its own service sharing one construction and lifetime.
+func (c *RoidsContainer) Provide(ctor any, opts ...ServiceOption) error {
+	if ftype := reflect.TypeOf(ctor); ftype != nil && ftype.Kind() == reflect.Func && hasMultipleResults(ftype) {
+		ctor = newResultsInjector(ctor)
+	}
+	implType, err := injectorOutput(ctor)
+	if err != nil {
+		c.recordError(serviceKey{specType: reflect.TypeOf(ctor)}, &err)
+		return err
+	}
+	lifetime := newServiceOptions(opts).lifetime
+	if lifetime == "" {
+		lifetime = core.StaticLifetime
+	}
+	return c.addService("", reflect.New(implType).Interface(), ctor, lifetime, opts)
+}
+
+// Gets the type of the service returned by the injector.
+func injectorOutput(impl any) (reflect.Type, error) {
+	if si, ok := impl.(*structInjector); ok {
+		return si.implType, nil
+	}
+	ftype := reflect.TypeOf(impl)
+	if ftype == nil || ftype.Kind() != reflect.Func {
+		return nil, core.NewInjectorError(ftype)
+	}
+	if err := checkInjectorOutputs(ftype); err != nil {
+		return nil, core.NewInjectorSignatureError(err, ftype)
+	}
+	return ftype.Out(0), nil
+}
+
+// Adds a service of the alias specification, resolving to the instance of the target service.
+// The alias has the lifetime and key of its target.
+func (c *RoidsContainer) addAlias(target *Service, implType reflect.Type, aliasType reflect.Type) error {
+	if !implType.AssignableTo(aliasType) {
+		return core.NewServiceError(aliasType, implType)
+	}
+	alias, err := c.addDerived(target, aliasType, target.Key, func(instance reflect.Value) reflect.Value {
+		return instance
+	})
+	if err != nil {
+		return err
+	}
+	alias.implType = implType
+	alias.aliasOf = target
+	return nil
+}
+
+// Adds a service of the specification and key, derived from the instance of the target service.
+// The service has the lifetime of its target, and depends on it.
+func (c *RoidsContainer) addDerived(target *Service, specType reflect.Type, key string, derive func(reflect.Value) reflect.Value) (*Service, error) {
+	// The injector of the service derives its instance from the instance of the target.
+	ftype := reflect.FuncOf([]reflect.Type{target.SpecType}, []reflect.Type{specType}, false)
+	injector := reflect.MakeFunc(ftype, func(args []reflect.Value) []reflect.Value {
+		instance := args[0]
+		if instance.Kind() == reflect.Interface {
+			if instance.IsNil() {
+				// The target injector returned a nil interface.
+				return []reflect.Value{reflect.Zero(specType)}
+			}
+			instance = instance.Elem()
+		}
+		return []reflect.Value{derive(instance).Convert(specType)}
+	})
+
+	service := &Service{SpecType: specType, Key: key}
+	if err := c.servicesGraph.addVertex(service); err != nil {
+		// A dependant added a placeholder vertex for the service, or it was registered before.
+		service = c.servicesGraph.getServiceByKey(specType, key)
+	}
+	service.Injector = injector.Interface()
+	service.implType = specType
+	service.lifetimeType = target.lifetimeType
+	service.lazy = target.lazy
+
+	depService, err := c.addDependency(service, target.SpecType, target.Key, dependencyEdge)
+	if err != nil {
+		return nil, err
+	}
+	service.params = []*dependency{{service: depService}}
+	return service, nil
+}
@@ -0,0 +1,115 @@
+package roids_test
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ShounakA/roids"
+	"github.com/ShounakA/roids/core"
+)
+
+type (
+	deleter interface {
+		Delete(k string)
+	}
+
+	cacheUser struct {
+		cache   ICache
+		deleter deleter
+	}
+)
+
+func TestProvide(t *testing.T) {
+	c := roids.NewContainer()
+	var calls atomic.Int32
+	err := c.Provide(func() *MyCache {
+		calls.Add(1)
+		return NewCache()
+	}, roids.As[ICache](), roids.As[deleter]())
+	if err != nil {
+		t.Error("Should provide constructors.", err.Error())
+	}
+	if err := c.Build(); err != nil {
+		t.Error("Should build the container.", err.Error())
+	}
+
+	cache := roids.InjectFrom[*MyCache](c)
+	if roids.InjectFrom[ICache](c) != cache || roids.InjectFrom[deleter](c) != cache {
+		t.Error("Aliases should resolve to the same instance.")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Should construct the instance once, got %d", calls.Load())
+	}
+}
+
+func TestProvide_Transient(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.Provide(NewCache, roids.WithLifetime(core.TransientLifetime), roids.As[ICache](), roids.As[deleter]())
+	_ = c.Provide(func(cache ICache, deleter deleter) *cacheUser {
+		return &cacheUser{cache: cache, deleter: deleter}
+	}, roids.WithLifetime(core.TransientLifetime))
+	_ = c.Build()
+
+	if roids.InjectFrom[ICache](c) == roids.InjectFrom[ICache](c) {
+		t.Error("Transient aliases should resolve to a new instance each time.")
+	}
+	user := roids.InjectFrom[*cacheUser](c)
+	if user.cache != user.deleter {
+		t.Error("Aliases should share the instance built for a dependant.")
+	}
+}
+
+func TestProvide_DisposedOnce(t *testing.T) {
+	c := roids.NewContainer()
+	rec := newRecorder()
+	_ = roids.AddInstanceTo[iRecorder](c, rec)
+	_ = c.Provide(newClosingDb, roids.As[IDbProvider](), roids.As[io.Closer]())
+	_ = c.Build()
+
+	if roids.InjectFrom[IDbProvider](c) != roids.InjectFrom[io.Closer](c) {
+		t.Error("Aliases should resolve to the same instance.")
+	}
+	_ = c.Start(context.Background())
+	if err := c.Shutdown(context.Background()); err != nil {
+		t.Error("Should shutdown the container.", err.Error())
+	}
+	if !reflect.DeepEqual(rec.Events(), []string{"start db", "stop db", "close db"}) {
+		t.Errorf("Should start, stop and dispose the instance once, got %v", rec.Events())
+	}
+}
+
+func TestProvide_Scoped(t *testing.T) {
+	c := roids.NewContainer()
+	rec := newRecorder()
+	_ = roids.AddInstanceTo[iRecorder](c, rec)
+	_ = c.Provide(newClosingDb, roids.WithLifetime(core.ScopedLifetime), roids.As[IDbProvider]())
+	_ = c.Build()
+
+	scope := c.CreateScope()
+	if roids.InjectFrom[IDbProvider](scope) != roids.InjectFrom[*closingDb](scope) {
+		t.Error("Scoped aliases should resolve to the instance of the scope.")
+	}
+	_ = scope.Close()
+	if !reflect.DeepEqual(rec.Events(), []string{"close db"}) {
+		t.Errorf("Should dispose the scoped instance once, got %v", rec.Events())
+	}
+}
+
+func TestAs_WithSpecification(t *testing.T) {
+	c := roids.NewContainer()
+	if err := c.AddStaticService(new(ICache), NewCache, roids.As[deleter]()); err != nil {
+		t.Error("Should add aliases to any service.", err.Error())
+	}
+	_ = c.Build()
+	if roids.InjectFrom[ICache](c) != roids.InjectFrom[deleter](c) {
+		t.Error("Aliases should resolve to the same instance.")
+	}
+
+	err := c.Provide(NewCache, roids.As[IDbProvider]())
+	if _, ok := err.(*core.ServiceError); !ok {
+		t.Errorf("Expected ServiceError, got %v", err)
+	}
+}
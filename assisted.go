@@ -0,0 +1,118 @@
+package roids
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/ShounakA/roids/core"
+)
+
+// Adds a factory of type F to the global container. See `AddFactoryTo`.
+func AddFactory[F any](ctor any, opts ...ServiceOption) error {
+	return AddFactoryTo[F](GetRoids(), ctor, opts...)
+}
+
+// Adds a factory of type F to the provided container, built from the constructor.
+// F is a func type taking the arguments known when it is called, e.g. `func(tenantID string) ITodoRepository`.
+// The constructor takes the same arguments first, followed by its dependencies,
+// e.g. `func(tenantID string, db IDbProvider) *TodoRepository`, and may return an error if F does.
+// The container resolves the dependencies and injects F like any other service, so it is static
+// unless `WithLifetime` is used. Dependencies are resolved when the factory is created, so use
+// `Provider[T]` parameters to resolve a dependency on each call.
+// `WithParamKey` and the other parameter options index the dependencies of the constructor, after the arguments of F.
+func AddFactoryTo[F any](c *RoidsContainer, ctor any, opts ...ServiceOption) error {
+	specType := reflect.TypeOf(new(F)).Elem()
+	injector, err := newFactoryInjector(specType, ctor)
+	if err != nil {
+		c.recordError(serviceKey{specType: specType}, &err)
+		return err
+	}
+	options := newServiceOptions(opts)
+	lifetime := options.lifetime
+	if lifetime == "" {
+		lifetime = core.StaticLifetime
+	}
+	return c.addService("", new(F), injector, lifetime, []ServiceOption{offsetParams(options, specType.NumIn())})
+}
+
+// Creates an injector taking the dependencies of the constructor and returning a factory of the func type,
+// which calls the constructor with its arguments followed by the dependencies.
+func newFactoryInjector(specType reflect.Type, ctor any) (any, error) {
+	if specType.Kind() != reflect.Func || specType.IsVariadic() {
+		return nil, core.NewInjectorSignatureError(fmt.Errorf("%s is not a non-variadic func type", specType), specType)
+	}
+	if err := checkInjectorOutputs(specType); err != nil {
+		return nil, core.NewInjectorSignatureError(err, specType)
+	}
+	ctorVal := reflect.ValueOf(ctor)
+	if ctorVal.Kind() != reflect.Func {
+		return nil, core.NewInjectorError(specType)
+	}
+	ctorType := ctorVal.Type()
+	if err := checkFactoryConstructor(specType, ctorType); err != nil {
+		return nil, core.NewInjectorSignatureError(err, specType)
+	}
+
+	args := specType.NumIn()
+	deps := make([]reflect.Type, ctorType.NumIn()-args)
+	for i := range deps {
+		deps[i] = ctorType.In(args + i)
+	}
+	ftype := reflect.FuncOf(deps, []reflect.Type{specType}, false)
+	injector := reflect.MakeFunc(ftype, func(depValues []reflect.Value) []reflect.Value {
+		factory := reflect.MakeFunc(specType, func(argValues []reflect.Value) []reflect.Value {
+			results := ctorVal.Call(append(append([]reflect.Value{}, argValues...), depValues...))
+			impl := reflect.New(specType.Out(0)).Elem()
+			if len(results) > 1 && !results[1].IsNil() {
+				return []reflect.Value{impl, results[1]}
+			}
+			impl.Set(results[0])
+			if specType.NumOut() > 1 {
+				return []reflect.Value{impl, reflect.Zero(errorType)}
+			}
+			return []reflect.Value{impl}
+		})
+		return []reflect.Value{factory}
+	})
+	return injector.Interface(), nil
+}
+
+// Checks that the constructor takes the arguments of the factory first, and returns what the factory returns.
+func checkFactoryConstructor(specType reflect.Type, ctorType reflect.Type) error {
+	if ctorType.IsVariadic() || ctorType.NumIn() < specType.NumIn() {
+		return fmt.Errorf("%s must take the arguments of %s first", ctorType, specType)
+	}
+	for i := 0; i < specType.NumIn(); i++ {
+		if !specType.In(i).AssignableTo(ctorType.In(i)) {
+			return fmt.Errorf("%s must take the arguments of %s first", ctorType, specType)
+		}
+	}
+	if err := checkInjectorOutputs(ctorType); err != nil {
+		return err
+	}
+	if !ctorType.Out(0).AssignableTo(specType.Out(0)) {
+		return fmt.Errorf("%s must return %s", ctorType, specType.Out(0))
+	}
+	if ctorType.NumOut() > specType.NumOut() {
+		return fmt.Errorf("%s can only return an error if %s does", ctorType, specType)
+	}
+	return nil
+}
+
+// Gets an option applying the options with their parameter indexes moved by the offset.
+func offsetParams(options *serviceOptions, offset int) ServiceOption {
+	return func(o *serviceOptions) {
+		paramKeys, optionalParams, groupParams := o.paramKeys, o.optionalParams, o.groupParams
+		*o = *options
+		o.paramKeys, o.optionalParams, o.groupParams = paramKeys, optionalParams, groupParams
+		for index, key := range options.paramKeys {
+			o.paramKeys[index-offset] = key
+		}
+		for index, optional := range options.optionalParams {
+			o.optionalParams[index-offset] = optional
+		}
+		for index, group := range options.groupParams {
+			o.groupParams[index-offset] = group
+		}
+	}
+}
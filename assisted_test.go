@@ -0,0 +1,104 @@
+package roids_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ShounakA/roids"
+	"github.com/ShounakA/roids/core"
+)
+
+type (
+	tenantRepoFactory func(tenantID string) (ITodoRepository, error)
+
+	tenantRepo struct {
+		tenantID string
+		db       IDbProvider
+	}
+
+	tenantService struct {
+		newRepo tenantRepoFactory
+	}
+)
+
+func (r *tenantRepo) DoStuff() error {
+	return nil
+}
+
+func newTenantRepo(tenantID string, db IDbProvider) (*tenantRepo, error) {
+	if tenantID == "" {
+		return nil, errors.New("no tenant")
+	}
+	return &tenantRepo{tenantID: tenantID, db: db}, nil
+}
+
+func TestAddFactory(t *testing.T) {
+	c := roids.NewContainer()
+	err := roids.AddFactoryTo[tenantRepoFactory](c, newTenantRepo)
+	if err != nil {
+		t.Fatal("Should add the factory.", err.Error())
+	}
+	_ = c.AddStaticService(new(IDbProvider), NewSqliteProvider)
+	_ = c.Provide(func(newRepo tenantRepoFactory) *tenantService {
+		return &tenantService{newRepo: newRepo}
+	})
+	if err := c.Validate(); err != nil {
+		t.Error("Should validate the dependencies of the factory.", err.Error())
+	}
+	if err := c.Build(); err != nil {
+		t.Fatal("Should build the factory.", err.Error())
+	}
+
+	service := roids.InjectFrom[*tenantService](c)
+	repo, err := service.newRepo("acme")
+	if err != nil {
+		t.Fatal("Should construct with the factory.", err.Error())
+	}
+	tenant := repo.(*tenantRepo)
+	if tenant.tenantID != "acme" || tenant.db != roids.InjectFrom[IDbProvider](c) {
+		t.Errorf("Should pass the arguments followed by the dependencies, got %v", tenant)
+	}
+	if other, _ := service.newRepo("globex"); other == repo {
+		t.Error("Should construct a new instance on each call.")
+	}
+	if _, err := service.newRepo(""); err == nil {
+		t.Error("Should return the error of the constructor.")
+	}
+}
+
+func TestAddFactory_ParamKey(t *testing.T) {
+	c := roids.NewContainer()
+	_ = roids.AddFactoryTo[func(string) ITodoRepository](c, func(tenantID string, db IDbProvider) *tenantRepo {
+		return &tenantRepo{tenantID: tenantID, db: db}
+	}, roids.WithParamKey(1, "replica"))
+	_ = c.AddKeyedStaticService("replica", new(IDbProvider), newReplicaProvider)
+	_ = c.Build()
+
+	newRepo := roids.InjectFrom[func(string) ITodoRepository](c)
+	if db := newRepo("acme").(*tenantRepo).db.(*SqliteProvider); db != roids.InjectKeyedFrom[IDbProvider](c, "replica") {
+		t.Error("Should key the dependencies by their index in the constructor.")
+	}
+}
+
+func TestAddFactory_MissingDependency(t *testing.T) {
+	c := roids.NewContainer()
+	_ = roids.AddFactoryTo[tenantRepoFactory](c, newTenantRepo)
+
+	var missing *core.ServiceNotRegisteredError
+	if err := c.Validate(); !errors.As(err, &missing) || missing.SpecType.Name() != "IDbProvider" {
+		t.Errorf("Should report the missing dependencies of the factory, got %v", err)
+	}
+}
+
+func TestAddFactory_Signature(t *testing.T) {
+	c := roids.NewContainer()
+	if err := roids.AddFactoryTo[tenantRepoFactory](c, func(db IDbProvider) *tenantRepo { return nil }); err == nil {
+		t.Error("Should require the constructor to take the arguments of the factory first.")
+	}
+	if err := roids.AddFactoryTo[func(string) ITodoRepository](c, newTenantRepo); err == nil {
+		t.Error("Should require the factory to return an error if the constructor does.")
+	}
+	if err := roids.AddFactoryTo[ITodoRepository](c, newTenantRepo); err == nil {
+		t.Error("Should require a func type.")
+	}
+}
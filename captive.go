@@ -0,0 +1,169 @@
+package roids
+
+import (
+	"github.com/ShounakA/roids/core"
+)
+
+// Policy applied when a static service captures a transient or scoped dependency,
+// keeping a single instance of it for the life of the container.
+type CaptivePolicy int
+
+const (
+	// Captive dependencies are allowed. The default for transient dependencies.
+	AllowCaptive CaptivePolicy = iota
+	// Captive dependencies are logged as warnings.
+	WarnCaptive
+	// Captive dependencies are reported as a `LifetimeMismatchError`. The default for scoped dependencies.
+	RejectCaptive
+)
+
+// A static service and a dependency it captures.
+type captiveEdge struct {
+	service    *Service
+	dependency *Service
+}
+
+// Gets the policy for static services capturing dependencies of the lifetime.
+func (c *RoidsContainer) captivePolicy(lifetime string) CaptivePolicy {
+	if policy, ok := c.captivePolicies[lifetime]; ok {
+		return policy
+	}
+	if lifetime == core.ScopedLifetime {
+		return RejectCaptive
+	}
+	return AllowCaptive
+}
+
+// Applies the captive policies to the edges. Warnings are logged, and rejected edges are returned as errors.
+func (c *RoidsContainer) checkCaptives(edges []captiveEdge) []error {
+	var errs []error
+	for _, edge := range edges {
+		err := core.NewLifetimeMismatchError(edge.service.SpecType, edge.service.lifetimeType, edge.dependency.SpecType, edge.dependency.lifetimeType)
+		switch c.captivePolicy(edge.dependency.lifetimeType) {
+		case WarnCaptive:
+			c.Logger.Warn(err.Error())
+		case RejectCaptive:
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// Gets the transient dependencies captured by static services that the captive policy rejects.
+// Scoped dependencies already fail the construction of static services with a `ScopedServiceError`.
+func (c *RoidsContainer) getRejectedCaptives() []error {
+	var errs []error
+	for _, static := range c.getStatics() {
+		errs = append(errs, c.rejectCaptives(static)...)
+	}
+	return errs
+}
+
+// Gets the transient dependencies captured by the static service that the captive policy rejects.
+func (c *RoidsContainer) rejectCaptives(static *Service) []error {
+	if c.captivePolicy(core.TransientLifetime) != RejectCaptive {
+		return nil
+	}
+	var errs []error
+	for _, edge := range getCaptives(static) {
+		if edge.dependency.lifetimeType == core.TransientLifetime {
+			errs = append(errs, core.NewLifetimeMismatchError(static.SpecType, static.lifetimeType, edge.dependency.SpecType, edge.dependency.lifetimeType))
+		}
+	}
+	return errs
+}
+
+// Gets the captive edges involving the newly added service: its own captive dependencies if it is static,
+// and the static services capturing it otherwise.
+func (c *RoidsContainer) getCaptivesOf(service *Service) []captiveEdge {
+	var edges []captiveEdge
+	for _, static := range c.servicesGraph.getStaticDependants(service, make(map[string]bool)) {
+		for _, edge := range getCaptives(static) {
+			if edge.service == service || edge.dependency == service {
+				edges = append(edges, edge)
+			}
+		}
+	}
+	return edges
+}
+
+// Gets the static services constructed with the service: the service itself if it is static,
+// and the static services depending on it through transient services and groups.
+func (graph *serviceGraph) getStaticDependants(service *Service, visited map[string]bool) []*Service {
+	if visited[service.Id] {
+		return nil
+	}
+	visited[service.Id] = true
+	if service.lifetimeType == core.StaticLifetime {
+		return []*Service{service}
+	}
+	if service.lifetimeType == core.ScopedLifetime && len(visited) > 1 {
+		// Scoped services are the captives themselves, not constructed with their dependants.
+		return nil
+	}
+	var statics []*Service
+	for _, dependant := range graph.getDependants(service) {
+		statics = append(statics, graph.getStaticDependants(dependant, visited)...)
+	}
+	return statics
+}
+
+// Gets the dependencies captured by the static service: transient services injected directly,
+// and scoped services constructed with it through transient services and groups.
+// Factory parameters resolve their service on demand, so transient services are not captured through them.
+func getCaptives(service *Service) []captiveEdge {
+	var edges []captiveEdge
+	for _, param := range service.allParams() {
+		if param.factory != nil {
+			continue
+		}
+		for _, dep := range expandGroup(param.service) {
+			if dep.lifetimeType == core.TransientLifetime {
+				edges = append(edges, captiveEdge{service: service, dependency: dep})
+			}
+		}
+	}
+	for _, scoped := range findScopedDependencies(service, make(map[string]bool)) {
+		edges = append(edges, captiveEdge{service: service, dependency: scoped})
+	}
+	return edges
+}
+
+// Finds the scoped services constructed along with the service, through its transient dependencies and groups.
+// Static dependencies are checked on their own.
+func findScopedDependencies(service *Service, visited map[string]bool) []*Service {
+	var found []*Service
+	for _, param := range service.allParams() {
+		found = append(found, findScoped(param.service, visited)...)
+	}
+	return found
+}
+
+// Finds the service if it is scoped, or the scoped services it is constructed with.
+func findScoped(service *Service, visited map[string]bool) []*Service {
+	if visited[service.Id] {
+		return nil
+	}
+	visited[service.Id] = true
+	switch {
+	case service.lifetimeType == core.ScopedLifetime:
+		return []*Service{service}
+	case service.lifetimeType == core.TransientLifetime:
+		return findScopedDependencies(service, visited)
+	case service.isGroup:
+		var found []*Service
+		for _, member := range service.members {
+			found = append(found, findScoped(member, visited)...)
+		}
+		return found
+	}
+	return nil
+}
+
+// Gets the members of the group, or the service itself if it is not a group.
+func expandGroup(service *Service) []*Service {
+	if service.isGroup {
+		return service.members
+	}
+	return []*Service{service}
+}
@@ -0,0 +1,107 @@
+package roids_test
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/ShounakA/roids"
+	"github.com/ShounakA/roids/core"
+)
+
+func TestCaptivePolicy_DefaultAllowsTransient(t *testing.T) {
+	c := roids.NewContainer()
+	if err := c.AddStaticService(new(testInterface), newTestObject); err != nil {
+		t.Error("Should be able to add simple dependencies.", err.Error())
+	}
+	if err := c.AddTransientService(new(dependedService), newDependedObject); err != nil {
+		t.Error("Static services can capture transient services by default.", err.Error())
+	}
+	if err := c.Validate(); err != nil {
+		t.Error("Static services can capture transient services by default.", err.Error())
+	}
+}
+
+func TestCaptivePolicy_RejectTransient(t *testing.T) {
+	c := roids.NewContainer(roids.WithCaptivePolicy(core.TransientLifetime, roids.RejectCaptive))
+	_ = c.AddStaticService(new(testInterface), newTestObject)
+
+	err := c.AddTransientService(new(dependedService), newDependedObject)
+	mismatch, ok := err.(*core.LifetimeMismatchError)
+	if !ok {
+		t.Fatalf("Expected LifetimeMismatchError, got %v", err)
+	}
+	if mismatch.SpecType.Name() != "testInterface" || mismatch.DependencySpecType.Name() != "dependedService" {
+		t.Errorf("Should report the offending edge, got %v", mismatch)
+	}
+	if !errors.As(c.Validate(), &mismatch) {
+		t.Error("Validate should report the captive dependency.")
+	}
+	if _, ok := c.Build().(*core.LifetimeMismatchError); !ok {
+		t.Error("Build should not construct the rejected captive dependency.")
+	}
+
+	c = roids.NewContainer(roids.WithCaptivePolicy(core.TransientLifetime, roids.RejectCaptive))
+	_ = c.AddTransientService(new(dependedService), newDependedObject)
+	if _, ok := c.AddStaticService(new(testInterface), newTestObject).(*core.LifetimeMismatchError); !ok {
+		t.Error("Should report the captive dependency when the static service is added last.")
+	}
+	if _, ok := c.Build().(*core.LifetimeMismatchError); !ok {
+		t.Error("Build should not construct the rejected captive dependency.")
+	}
+	if _, err := roids.InjectFromE[testInterface](c); err == nil {
+		t.Error("Should not inject the static service capturing the rejected dependency.")
+	}
+
+	c = roids.NewContainer(roids.WithLazyStatics(), roids.WithCaptivePolicy(core.TransientLifetime, roids.RejectCaptive))
+	_ = c.AddTransientService(new(dependedService), newDependedObject)
+	_ = c.AddStaticService(new(testInterface), newTestObject)
+	if _, err := roids.InjectFromE[testInterface](c); !errors.As(err, &mismatch) {
+		t.Errorf("Should not construct lazy static services capturing the rejected dependency, got %v", err)
+	}
+}
+
+func TestCaptivePolicy_WarnTransient(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	c := roids.NewContainer(roids.WithLogger(logger), roids.WithCaptivePolicy(core.TransientLifetime, roids.WarnCaptive))
+	_ = c.AddStaticService(new(testInterface), newTestObject)
+
+	if err := c.AddTransientService(new(dependedService), newDependedObject); err != nil {
+		t.Error("Warnings should not fail registration.", err.Error())
+	}
+	if !strings.Contains(logs.String(), "level=WARN") || !strings.Contains(logs.String(), "dependedService") {
+		t.Errorf("Should log the captive dependency, got %s", logs.String())
+	}
+}
+
+func TestCaptivePolicy_ProviderIsNotCaptive(t *testing.T) {
+	c := roids.NewContainer(roids.WithCaptivePolicy(core.TransientLifetime, roids.RejectCaptive))
+	if err := c.AddStaticService(new(iRepoFactory), newRepoFactory); err != nil {
+		t.Error("Should be able to add factories.", err.Error())
+	}
+	if err := c.AddTransientService(new(ITodoRepository), func() *TodoRepository { return &TodoRepository{} }); err != nil {
+		t.Error("Services resolved on demand are not captured.", err.Error())
+	}
+}
+
+func TestCaptivePolicy_Scoped(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddStaticService(new(myInterface), func(repo ITodoRepository) *shape { return &shape{} })
+	_ = c.AddTransientService(new(ITodoRepository), func(db IDbProvider) *TodoRepository { return &TodoRepository{db: db} })
+
+	err := c.AddScopedService(new(IDbProvider), NewSqliteProvider)
+	if _, ok := err.(*core.LifetimeMismatchError); !ok {
+		t.Errorf("Static services cannot capture scoped services by default, got %v", err)
+	}
+
+	c = roids.NewContainer(roids.WithCaptivePolicy(core.ScopedLifetime, roids.AllowCaptive))
+	_ = c.AddStaticService(new(myInterface), func(repo ITodoRepository) *shape { return &shape{} })
+	_ = c.AddTransientService(new(ITodoRepository), func(db IDbProvider) *TodoRepository { return &TodoRepository{db: db} })
+	_ = c.AddScopedService(new(IDbProvider), NewSqliteProvider)
+	if err := c.Validate(); err != nil {
+		t.Error("Should allow scoped captives when configured.", err.Error())
+	}
+}
@@ -6,6 +6,9 @@ const StaticLifetime string = "Static"
 // Constant to ID Transient lifetimes
 const TransientLifetime string = "Transient"
 
+// Constant to ID Scoped lifetimes
+const ScopedLifetime string = "Scoped"
+
 type ConfigType int
 
 const (
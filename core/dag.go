@@ -4,6 +4,7 @@ import (
 	"container/list"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 )
@@ -176,15 +177,20 @@ func (g *AcyclicGraph) TraverseTopological(tAction traverseAction) error {
 
 	inDegree := g.calculateInDegrees()
 
-	// Initialize a queue with all nodes that have an in-degree of 0.
-	queue := list.New()
+	// Initialize a queue with all nodes that have an in-degree of 0, sorted by ID so the order is stable.
+	var roots []string
 	for id, degree := range inDegree {
 		if degree == 0 {
-			node := g.nodes[id]
-			node.isRoot = true // Set the root flag, similar to TraverseBF
-			queue.PushBack(node)
+			roots = append(roots, id)
 		}
 	}
+	sort.Strings(roots)
+	queue := list.New()
+	for _, id := range roots {
+		node := g.nodes[id]
+		node.isRoot = true // Set the root flag, similar to TraverseBF
+		queue.PushBack(node)
+	}
 
 	visitedCount := 0
 	for queue.Len() > 0 {
@@ -379,6 +385,49 @@ func (g *AcyclicGraph) TraverseTopologicalTo(end string, tAction traverseAction)
 	return nil
 }
 
+// TopologicalLayers groups the nodes into layers, where every node only has edges from nodes in earlier layers.
+// Nodes in the same layer are independent of each other, and are sorted by ID.
+// It returns an error if the graph contains a cycle.
+func (g *AcyclicGraph) TopologicalLayers() ([][]string, error) {
+	g.muDAG.RLock()
+	defer g.muDAG.RUnlock()
+
+	inDegree := g.calculateInDegrees()
+
+	var layer []string
+	for id, degree := range inDegree {
+		if degree == 0 {
+			layer = append(layer, id)
+		}
+	}
+
+	var layers [][]string
+	visitedCount := 0
+	for len(layer) > 0 {
+		sort.Strings(layer)
+		layers = append(layers, layer)
+		visitedCount += len(layer)
+
+		// Children whose parents have all been layered form the next layer.
+		var next []string
+		for _, id := range layer {
+			for _, child := range g.nodes[id].children {
+				inDegree[child.id]--
+				if inDegree[child.id] == 0 {
+					next = append(next, child.id)
+				}
+			}
+		}
+		layer = next
+	}
+
+	if visitedCount != len(g.nodes) {
+		return nil, errors.New("graph has a cycle, topological layering not possible")
+	}
+
+	return layers, nil
+}
+
 // hasCycleHelper is a utility function to check for cycles in the graph
 func (g *AcyclicGraph) hasCycleHelper(node *node, visited map[string]bool, recStack map[string]bool) bool {
 	if recStack[node.id] {
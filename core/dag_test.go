@@ -141,3 +141,27 @@ func TestTraverseBFFrom(t *testing.T) {
 	sort.Ints(level0)
 	assert.Equal(t, []int{3, 4}, level0, "Level 0 nodes (roots) should be {3, 4}")
 }
+
+func TestTopologicalLayers(t *testing.T) {
+	graph := NewGraph()
+	id1, _ := graph.AddVertex(&testType{Val: 1})
+	id2, _ := graph.AddVertex(&testType{Val: 2})
+	id3, _ := graph.AddVertex(&testType{Val: 3})
+	id4, _ := graph.AddVertex(&testType{Val: 4})
+	id5, _ := graph.AddVertex(&testType{Val: 5})
+
+	assert.NoError(t, graph.AddEdge(id1, id3))
+	assert.NoError(t, graph.AddEdge(id2, id3))
+	assert.NoError(t, graph.AddEdge(id3, id4))
+	assert.NoError(t, graph.AddEdge(id1, id4))
+
+	layers, err := graph.TopologicalLayers()
+	assert.NoError(t, err)
+	assert.Len(t, layers, 3, "Should have 3 layers")
+
+	level0 := []string{id1, id2, id5}
+	sort.Strings(level0)
+	assert.Equal(t, level0, layers[0], "Layer 0 should be the sorted roots")
+	assert.Equal(t, []string{id3}, layers[1], "Layer 1 should only depend on roots")
+	assert.Equal(t, []string{id4}, layers[2], "Layer 2 should come after all its parents")
+}
@@ -3,6 +3,7 @@ package core
 import (
 	"fmt"
 	"reflect"
+	"strings"
 )
 
 type (
@@ -32,6 +33,55 @@ type (
 		SpecType reflect.Type
 	}
 
+	ConstructionError struct {
+		err      error
+		SpecType reflect.Type
+	}
+
+	ServiceNotRegisteredError struct {
+		SpecType reflect.Type
+		// Services requiring the missing one, from the outermost dependant to the direct one. Empty if unknown.
+		Path []string
+	}
+
+	LifetimeMismatchError struct {
+		SpecType           reflect.Type
+		Lifetime           string
+		DependencySpecType reflect.Type
+		DependencyLifetime string
+	}
+
+	ContainerNotBuiltError struct {
+		SpecType reflect.Type
+	}
+
+	DeferredRecursionError struct {
+		SpecType           reflect.Type
+		DependencySpecType reflect.Type
+	}
+
+	DisposeError struct {
+		err      error
+		SpecType reflect.Type
+	}
+
+	HookError struct {
+		err      error
+		SpecType reflect.Type
+	}
+
+	ScopedServiceError struct {
+		SpecType reflect.Type
+	}
+
+	DecoratorError struct {
+		SpecType reflect.Type
+	}
+
+	ScopeClosedError struct {
+		SpecType reflect.Type
+	}
+
 	UnknownError struct {
 		err error
 	}
@@ -53,7 +103,10 @@ func NewServiceError(spec reflect.Type, impl reflect.Type) *ServiceError {
 }
 
 func (e *ServiceError) Error() string {
-	return fmt.Sprintf("[%s] '%s' must implement '%s' to be added as a service.", e.SpecType, e.SpecType, e.ImplType)
+	if e.SpecType.Kind() == reflect.Interface {
+		return fmt.Sprintf("[%s] '%s' must implement '%s' to be added as a service.", e.SpecType, e.ImplType, e.SpecType)
+	}
+	return fmt.Sprintf("[%s] '%s' must be assignable to '%s' to be added as a service.", e.SpecType, e.ImplType, e.SpecType)
 }
 
 func NewInjectorError(spec reflect.Type) *InjectorError {
@@ -62,8 +115,18 @@ func NewInjectorError(spec reflect.Type) *InjectorError {
 	}
 }
 
+func NewInjectorSignatureError(err error, spec reflect.Type) *InjectorError {
+	return &InjectorError{
+		err:      err,
+		SpecType: spec,
+	}
+}
+
 func (e *InjectorError) Error() string {
-	return fmt.Sprintf("[%s] Injector is not a function. -> %s", e.SpecType, e.err.Error())
+	if e.err == nil {
+		return fmt.Sprintf("[%s] Injector is not a function.", e.SpecType)
+	}
+	return fmt.Sprintf("[%s] Invalid injector signature. -> %s", e.SpecType, e.err.Error())
 }
 
 func NewCircularDependencyError(err error, spec reflect.Type) *CircularDependencyError {
@@ -97,7 +160,136 @@ func NewInvalidLifetimeError(err error, spec reflect.Type) *InvalidLifetimeError
 }
 
 func (e *InvalidLifetimeError) Error() string {
-	return fmt.Sprintf("[%s] Invalid lifetime. Valid  lifetimes are: %s and %s", e.SpecType, StaticLifetime, TransientLifetime)
+	return fmt.Sprintf("[%s] Invalid lifetime. Valid  lifetimes are: %s, %s and %s", e.SpecType, StaticLifetime, TransientLifetime, ScopedLifetime)
+}
+
+func NewConstructionError(err error, spec reflect.Type) *ConstructionError {
+	return &ConstructionError{
+		err:      err,
+		SpecType: spec,
+	}
+}
+
+func (e *ConstructionError) Error() string {
+	return fmt.Sprintf("[%s] Failed to construct service. -> %s", e.SpecType, e.err.Error())
+}
+
+func (e *ConstructionError) Unwrap() error {
+	return e.err
+}
+
+func NewServiceNotRegisteredError(spec reflect.Type) *ServiceNotRegisteredError {
+	return &ServiceNotRegisteredError{
+		SpecType: spec,
+	}
+}
+
+func NewMissingDependencyError(spec reflect.Type, path []string) *ServiceNotRegisteredError {
+	return &ServiceNotRegisteredError{
+		SpecType: spec,
+		Path:     path,
+	}
+}
+
+func (e *ServiceNotRegisteredError) Error() string {
+	if len(e.Path) > 0 {
+		return fmt.Sprintf("[%s] Service has not been registered. Required by %s", e.SpecType, strings.Join(e.Path, " -> "))
+	}
+	return fmt.Sprintf("[%s] Service has not been registered.", e.SpecType)
+}
+
+func NewLifetimeMismatchError(spec reflect.Type, lifetime string, depSpec reflect.Type, depLifetime string) *LifetimeMismatchError {
+	return &LifetimeMismatchError{
+		SpecType:           spec,
+		Lifetime:           lifetime,
+		DependencySpecType: depSpec,
+		DependencyLifetime: depLifetime,
+	}
+}
+
+func (e *LifetimeMismatchError) Error() string {
+	return fmt.Sprintf("[%s] %s service cannot depend on %s service %s.", e.SpecType, e.Lifetime, e.DependencyLifetime, e.DependencySpecType)
+}
+
+func NewDeferredRecursionError(spec reflect.Type, depSpec reflect.Type) *DeferredRecursionError {
+	return &DeferredRecursionError{
+		SpecType:           spec,
+		DependencySpecType: depSpec,
+	}
+}
+
+func (e *DeferredRecursionError) Error() string {
+	return fmt.Sprintf("[%s] Deferred %s was resolved while constructing the service, but depends on it. Resolve it after construction.", e.SpecType, e.DependencySpecType)
+}
+
+func NewContainerNotBuiltError(spec reflect.Type) *ContainerNotBuiltError {
+	return &ContainerNotBuiltError{
+		SpecType: spec,
+	}
+}
+
+func (e *ContainerNotBuiltError) Error() string {
+	return fmt.Sprintf("[%s] Static service has not been built. Call Build() before injecting it.", e.SpecType)
+}
+
+func NewDisposeError(err error, spec reflect.Type) *DisposeError {
+	return &DisposeError{
+		err:      err,
+		SpecType: spec,
+	}
+}
+
+func (e *DisposeError) Error() string {
+	return fmt.Sprintf("[%s] Failed to dispose service. -> %s", e.SpecType, e.err.Error())
+}
+
+func (e *DisposeError) Unwrap() error {
+	return e.err
+}
+
+func NewHookError(err error, spec reflect.Type) *HookError {
+	return &HookError{
+		err:      err,
+		SpecType: spec,
+	}
+}
+
+func (e *HookError) Error() string {
+	return fmt.Sprintf("[%s] Lifecycle hook failed. -> %s", e.SpecType, e.err.Error())
+}
+
+func (e *HookError) Unwrap() error {
+	return e.err
+}
+
+func NewScopedServiceError(spec reflect.Type) *ScopedServiceError {
+	return &ScopedServiceError{
+		SpecType: spec,
+	}
+}
+
+func (e *ScopedServiceError) Error() string {
+	return fmt.Sprintf("[%s] Scoped service can only be injected from a scope.", e.SpecType)
+}
+
+func NewDecoratorError(spec reflect.Type) *DecoratorError {
+	return &DecoratorError{
+		SpecType: spec,
+	}
+}
+
+func (e *DecoratorError) Error() string {
+	return fmt.Sprintf("[%s] Cannot decorate a service that was provided as an instance or has already been built.", e.SpecType)
+}
+
+func NewScopeClosedError(spec reflect.Type) *ScopeClosedError {
+	return &ScopeClosedError{
+		SpecType: spec,
+	}
+}
+
+func (e *ScopeClosedError) Error() string {
+	return fmt.Sprintf("[%s] Cannot inject from a scope that has been closed.", e.SpecType)
 }
 
 func NewUnknownError(err error) *UnknownError {
@@ -0,0 +1,90 @@
+package roids
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/ShounakA/roids/core"
+)
+
+// A function wrapping the instance of a service, added with `Decorate`.
+type serviceDecorator struct {
+	// Function taking the wrapped instance followed by its dependencies, and returning the wrapper.
+	injector any
+	// The dependency resolved for each parameter after the wrapped instance, in order.
+	params []*dependency
+}
+
+// Wraps the service of the specification in the global container with the decorator.
+// See `DecorateTo`.
+func Decorate[T any](decorator any, opts ...ServiceOption) error {
+	return DecorateTo[T](GetRoids(), decorator, opts...)
+}
+
+// Wraps the service of the specification in the provided container with the decorator.
+// The decorator is a function like `func(inner T, deps...) T`, optionally returning an error as well.
+// Decorators are applied in the order they are added, so dependants receive the wrapper of the last one.
+// The service must be registered before it is decorated, and decorated before the container is built.
+// Instances added with `AddInstance`, and static services that were already built, cannot be decorated.
+// `WithParamKey` and the other parameter options index the parameters of the decorator, including the inner one.
+func DecorateTo[T any](c *RoidsContainer, decorator any, opts ...ServiceOption) (err error) {
+	specType := reflect.TypeOf(new(T)).Elem()
+	defer c.recordDecoratorError(serviceKey{specType: specType}, &err)
+	service := c.servicesGraph.getServiceByType(specType)
+	if service == nil || !service.registered() {
+		return core.NewServiceNotRegisteredError(specType)
+	}
+	if service.provided || service.created {
+		// The instance is never constructed again, so the decorator would not run.
+		return core.NewDecoratorError(specType)
+	}
+
+	if reflect.ValueOf(decorator).Kind() != reflect.Func {
+		return core.NewInjectorError(specType)
+	}
+	ftype := reflect.TypeOf(decorator)
+	if ftype.NumIn() == 0 || ftype.In(0) != specType {
+		return core.NewInjectorSignatureError(fmt.Errorf("%s must take the decorated %s as its first parameter", ftype, specType), specType)
+	}
+	if err := checkInjectorOutputs(ftype); err != nil {
+		return core.NewInjectorSignatureError(err, specType)
+	}
+	if implType := ftype.Out(0); !implType.AssignableTo(specType) {
+		return core.NewServiceError(specType, implType)
+	}
+
+	options := newServiceOptions(opts)
+	for index := range options.paramKeys {
+		if index < 1 || index >= ftype.NumIn() {
+			return core.NewInjectorSignatureError(fmt.Errorf("no dependency at index %d to key", index), specType)
+		}
+	}
+
+	// The dependencies of the decorator are dependencies of the service it wraps.
+	d := &serviceDecorator{injector: decorator, params: make([]*dependency, ftype.NumIn()-1)}
+	for i := 1; i < ftype.NumIn(); i++ {
+		d.params[i-1], err = c.addParam(service, ftype.In(i), options.paramKeys[i], options.optionalParams[i])
+		if err != nil {
+			return err
+		}
+	}
+	service.decorators = append(service.decorators, d)
+	return nil
+}
+
+// Wraps the instance of the service with each of its decorators, innermost first.
+// args resolves the dependencies of each decorator.
+func (c *RoidsContainer) decorate(service *Service, instance *any, args func([]*dependency) ([]reflect.Value, error)) (*any, error) {
+	for _, d := range service.decorators {
+		argValues, err := args(d.params)
+		if err != nil {
+			return nil, err
+		}
+		argValues = append([]reflect.Value{valueOf(*instance, service.SpecType)}, argValues...)
+		instance, err = callInjector(d.injector, argValues, service.SpecType)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return instance, nil
+}
@@ -0,0 +1,114 @@
+package roids_test
+
+import (
+	"testing"
+
+	"github.com/ShounakA/roids"
+	"github.com/ShounakA/roids/core"
+)
+
+type namedDb struct {
+	inner IDbProvider
+	name  string
+	cache ICache
+}
+
+func (d *namedDb) Close() error {
+	return d.inner.Close()
+}
+
+func withName(name string) func(IDbProvider) IDbProvider {
+	return func(inner IDbProvider) IDbProvider {
+		return &namedDb{inner: inner, name: name}
+	}
+}
+
+func TestDecorate(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddStaticService(new(IDbProvider), NewSqliteProvider)
+	_ = c.AddStaticService(new(ICache), NewCache)
+	_ = c.AddTransientService(new(ITodoRepository), NewTodoRepository)
+
+	if err := roids.DecorateTo[IDbProvider](c, withName("metrics")); err != nil {
+		t.Error("Should decorate registered services.", err.Error())
+	}
+	err := roids.DecorateTo[IDbProvider](c, func(inner IDbProvider, cache ICache) IDbProvider {
+		return &namedDb{inner: inner, name: "caching", cache: cache}
+	})
+	if err != nil {
+		t.Error("Should decorate with dependencies.", err.Error())
+	}
+	if err := c.Build(); err != nil {
+		t.Error("Should build the container.", err.Error())
+	}
+
+	outer, ok := roids.InjectFrom[IDbProvider](c).(*namedDb)
+	if !ok || outer.name != "caching" || outer.cache != roids.InjectFrom[ICache](c) {
+		t.Fatal("Should inject the last decorator with its dependencies.")
+	}
+	inner, ok := outer.inner.(*namedDb)
+	if !ok || inner.name != "metrics" {
+		t.Fatal("Should apply decorators in the order they are added.")
+	}
+	if _, ok := inner.inner.(*SqliteProvider); !ok {
+		t.Error("Should keep the original injector.")
+	}
+
+	repo := roids.InjectFrom[ITodoRepository](c).(*TodoRepository)
+	if repo.db != outer {
+		t.Error("Dependants should receive the decorated service.")
+	}
+}
+
+func TestDecorate_Transient(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddTransientService(new(IDbProvider), NewSqliteProvider)
+	_ = roids.DecorateTo[IDbProvider](c, withName("logging"))
+	_ = c.Build()
+
+	first := roids.InjectFrom[IDbProvider](c).(*namedDb)
+	second := roids.InjectFrom[IDbProvider](c).(*namedDb)
+	if first == second || first.inner == second.inner {
+		t.Error("Transient services should be decorated on each injection.")
+	}
+}
+
+func TestDecorate_Errors(t *testing.T) {
+	c := roids.NewContainer()
+	err := roids.DecorateTo[IDbProvider](c, withName("metrics"))
+	if _, ok := err.(*core.ServiceNotRegisteredError); !ok {
+		t.Errorf("Expected ServiceNotRegisteredError, got %v", err)
+	}
+
+	_ = c.AddStaticService(new(IDbProvider), NewSqliteProvider)
+	err = roids.DecorateTo[IDbProvider](c, func(cache ICache) IDbProvider { return nil })
+	if _, ok := err.(*core.InjectorError); !ok {
+		t.Errorf("Decorators must take the inner service first, got %v", err)
+	}
+
+	_ = c.AddTransientService(new(ITodoRepository), func(db IDbProvider) *TodoRepository {
+		return &TodoRepository{db: db}
+	})
+	err = roids.DecorateTo[IDbProvider](c, func(inner IDbProvider, repo ITodoRepository) IDbProvider { return inner })
+	if _, ok := err.(*core.CircularDependencyError); !ok {
+		t.Errorf("Expected CircularDependencyError, got %v", err)
+	}
+}
+
+func TestDecorate_ConstructedServices(t *testing.T) {
+	c := roids.NewContainer()
+	_ = roids.AddInstanceTo[IDbProvider](c, NewSqliteProvider())
+	err := roids.DecorateTo[IDbProvider](c, withName("metrics"))
+	if _, ok := err.(*core.DecoratorError); !ok {
+		t.Errorf("Provided instances cannot be decorated, got %v", err)
+	}
+
+	_ = c.AddStaticService(new(ICache), NewCache)
+	if err := c.Build(); err != nil {
+		t.Fatal("Should build the container.", err.Error())
+	}
+	err = roids.DecorateTo[ICache](c, func(inner ICache) ICache { return inner })
+	if _, ok := err.(*core.DecoratorError); !ok {
+		t.Errorf("Built static services cannot be decorated, got %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package roids
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// Injector parameter resolving T on first use, for services that depend on each other.
+// Unlike `Lazy`, the dependency on T does not order construction and can form a cycle,
+// e.g. an event dispatcher constructed with its handlers, which publish through a `Deferred` dispatcher.
+// Resolving T while the service is constructed fails with `core.DeferredRecursionError` if T depends on the service.
+type Deferred[T any] struct {
+	state *deferredState
+}
+
+// Instance shared by the copies of a `Deferred`.
+type deferredState struct {
+	mu       sync.Mutex
+	get      func() (any, error)
+	instance any
+	resolved bool
+	// True while the injector of the service the parameter is injected into runs.
+	constructing atomic.Bool
+}
+
+// Factories whose dependency is excluded from the construction order, like `Deferred`.
+type deferredFactory interface {
+	factory
+	// Gets the flag set while the injector of the dependant runs.
+	constructing() *atomic.Bool
+}
+
+// Type of the deferredFactory interface, used to detect dependencies excluded from the construction order.
+var deferredType = reflect.TypeOf((*deferredFactory)(nil)).Elem()
+
+// Gets the instance of T, resolving it on first use. Panics if it cannot be resolved.
+func (d Deferred[T]) Get() T {
+	impl, err := d.GetE()
+	if err != nil {
+		panic(err)
+	}
+	return impl
+}
+
+// Gets the instance of T, resolving it on first use. Returns an error instead of panicking when it cannot be resolved.
+// Failed resolutions are retried on the next access.
+func (d Deferred[T]) GetE() (T, error) {
+	if d.state == nil {
+		return getFactory[T](nil)
+	}
+	return getFactory[T](d.state.resolve)
+}
+
+func (Deferred[T]) factoryOf() reflect.Type {
+	return reflect.TypeOf(new(T)).Elem()
+}
+
+func (Deferred[T]) newFactory(get func() (any, error)) any {
+	return Deferred[T]{state: &deferredState{get: get}}
+}
+
+func (d Deferred[T]) constructing() *atomic.Bool {
+	if d.state == nil {
+		return new(atomic.Bool)
+	}
+	return &d.state.constructing
+}
+
+// Gets the instance, resolving it if it has not been resolved yet.
+func (s *deferredState) resolve() (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.resolved {
+		instance, err := s.get()
+		if err != nil {
+			return nil, err
+		}
+		s.instance, s.resolved = instance, true
+	}
+	return s.instance, nil
+}
+
+// Sets the constructing flag of the `Deferred` arguments, including the fields of parameter objects.
+func setConstructing(args []reflect.Value, constructing bool) {
+	for _, arg := range args {
+		switch {
+		case !arg.IsValid():
+		case arg.Type().Implements(deferredType):
+			arg.Interface().(deferredFactory).constructing().Store(constructing)
+		case isObject(arg.Type(), inType):
+			fields := make([]reflect.Value, 0, arg.NumField())
+			for i := 0; i < arg.NumField(); i++ {
+				if arg.Type().Field(i).IsExported() {
+					fields = append(fields, arg.Field(i))
+				}
+			}
+			setConstructing(fields, constructing)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package roids_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ShounakA/roids"
+	"github.com/ShounakA/roids/core"
+)
+
+type (
+	iDispatcher interface {
+		Publish(event string) []string
+	}
+
+	iEventHandler interface {
+		Handle(event string) string
+	}
+
+	dispatcher struct {
+		handler iEventHandler
+	}
+
+	// Handles events by publishing a follow-up event through the dispatcher that calls it.
+	eventHandler struct {
+		dispatcher roids.Deferred[iDispatcher]
+	}
+)
+
+func (d *dispatcher) Publish(event string) []string {
+	return []string{d.handler.Handle(event)}
+}
+
+func (h *eventHandler) Handle(event string) string {
+	return "handled " + event
+}
+
+func newDispatcher(handler iEventHandler) *dispatcher {
+	return &dispatcher{handler: handler}
+}
+
+func newEventHandler(dispatcher roids.Deferred[iDispatcher]) *eventHandler {
+	return &eventHandler{dispatcher: dispatcher}
+}
+
+func TestDeferred(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddStaticService(new(iDispatcher), newDispatcher)
+	if err := c.AddStaticService(new(iEventHandler), newEventHandler); err != nil {
+		t.Fatal("Deferred dependencies should be allowed to form a cycle.", err.Error())
+	}
+	if err := c.Validate(); err != nil {
+		t.Error("Should validate deferred cycles.", err.Error())
+	}
+	if err := c.Build(); err != nil {
+		t.Fatal("Should build deferred cycles.", err.Error())
+	}
+
+	handler := roids.InjectFrom[iEventHandler](c).(*eventHandler)
+	dispatcher, err := handler.dispatcher.GetE()
+	if err != nil {
+		t.Fatal("Should resolve the deferred dependency after construction.", err.Error())
+	}
+	if dispatcher != roids.InjectFrom[iDispatcher](c) {
+		t.Error("Should resolve the static instance.")
+	}
+	if events := dispatcher.Publish("created"); events[0] != "handled created" {
+		t.Errorf("Should publish to the handler, got %v", events)
+	}
+}
+
+func TestDeferred_Cycle(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddStaticService(new(iDispatcher), newDispatcher)
+	err := c.AddStaticService(new(iEventHandler), func(dispatcher iDispatcher) *eventHandler {
+		return &eventHandler{}
+	})
+	if _, ok := err.(*core.CircularDependencyError); !ok {
+		t.Errorf("Only deferred dependencies should form a cycle, got %v", err)
+	}
+}
+
+func TestDeferred_Recursion(t *testing.T) {
+	for _, lifetime := range []string{core.StaticLifetime, core.TransientLifetime} {
+		c := roids.NewContainer()
+		_ = c.Provide(newDispatcher, roids.WithLifetime(lifetime), roids.As[iDispatcher]())
+		_ = c.Provide(func(dispatcher roids.Deferred[iDispatcher]) (*eventHandler, error) {
+			_, err := dispatcher.GetE()
+			return newEventHandler(dispatcher), err
+		}, roids.WithLifetime(lifetime), roids.As[iEventHandler]())
+
+		err := c.Build()
+		if lifetime == core.TransientLifetime {
+			_, err = roids.InjectFromE[iDispatcher](c)
+		}
+		var recursion *core.DeferredRecursionError
+		if !errors.As(err, &recursion) {
+			t.Fatalf("Should detect %s deferred resolutions recursing into construction, got %v", lifetime, err)
+		}
+		if recursion.DependencySpecType.Name() != "iDispatcher" {
+			t.Errorf("Should report the deferred dependency, got %v", recursion)
+		}
+	}
+}
+
+func TestDeferred_DuringConstruction(t *testing.T) {
+	// Deferred dependencies do not order construction, so the static dependency is built on demand
+	// whichever of the two services is built first.
+	for i := 0; i < 20; i++ {
+		c := roids.NewContainer()
+		var resolved IDbProvider
+		_ = c.AddStaticService(new(ICache), func(db roids.Deferred[IDbProvider]) (*MyCache, error) {
+			var err error
+			resolved, err = db.GetE()
+			return NewCache(), err
+		})
+		_ = c.AddStaticService(new(IDbProvider), NewSqliteProvider)
+		if err := c.Build(); err != nil {
+			t.Fatal("Should resolve deferred dependencies during construction when they do not recurse.", err.Error())
+		}
+		if resolved != roids.InjectFrom[IDbProvider](c) {
+			t.Fatal("Should resolve the static instance built by the container.")
+		}
+	}
+}
+
+func TestDeferred_Scoped(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddStaticService(new(ICache), NewCache)
+	_ = c.AddScopedService(new(iRequestLogger), newRequestLogger)
+	_ = c.AddTransientService(new(iUnitOfWork), func(logger roids.Deferred[iRequestLogger]) *unitOfWork {
+		return &unitOfWork{logger: logger.Get()}
+	})
+	_ = c.Build()
+
+	scope := c.CreateScope()
+	defer scope.Close()
+	work, err := roids.InjectFromE[iUnitOfWork](scope)
+	if err != nil {
+		t.Fatal("Should resolve deferred dependencies from the scope.", err.Error())
+	}
+	if work.Logger() != roids.InjectFrom[iRequestLogger](scope) {
+		t.Error("Should resolve the scoped instance.")
+	}
+}
@@ -0,0 +1,203 @@
+package roids
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/ShounakA/roids/core"
+)
+
+// Name of the struct tag marking fields to inject, e.g. `roids:"inject,optional"` or `roids:"inject,key=replica"`.
+const injectTag = "roids"
+
+// Injector constructing a struct and filling its tagged fields, created by `Struct`.
+type structInjector struct {
+	// Function with a parameter for each injected field, returning a pointer to the struct.
+	injector any
+	// Pointer to the struct type returned by the injector.
+	implType reflect.Type
+	fields   []*injectField
+	// Error found in the struct tags, reported when the service is added.
+	err error
+}
+
+// A struct field to inject, or to register for result objects.
+type injectField struct {
+	index    int
+	key      string
+	optional bool
+	group    bool
+}
+
+// Creates an injector that constructs a new T and fills its exported fields tagged with `roids:"inject"`.
+// Use it as the implementation when adding a service, e.g. `AddStaticService(new(IHandler), roids.Struct[Handler]())`.
+// The injected fields are dependencies of the service, just like injector parameters.
+func Struct[T any]() any {
+	structType := reflect.TypeOf(new(T)).Elem()
+	fields, err := getInjectFields(structType)
+	if err != nil {
+		return &structInjector{implType: reflect.PointerTo(structType), err: err}
+	}
+
+	in := make([]reflect.Type, len(fields))
+	for i, field := range fields {
+		in[i] = structType.Field(field.index).Type
+	}
+	ftype := reflect.FuncOf(in, []reflect.Type{reflect.PointerTo(structType)}, false)
+	injector := reflect.MakeFunc(ftype, func(args []reflect.Value) []reflect.Value {
+		instance := reflect.New(structType)
+		for i, field := range fields {
+			instance.Elem().Field(field.index).Set(args[i])
+		}
+		return []reflect.Value{instance}
+	})
+	return &structInjector{injector: injector.Interface(), implType: reflect.PointerTo(structType), fields: fields}
+}
+
+// Fills the tagged fields of the struct pointed to by target from the global container.
+func InjectInto(target any) error {
+	return GetRoids().InjectInto(target)
+}
+
+// Fills the tagged fields of the struct pointed to by target from the container.
+func (c *RoidsContainer) InjectInto(target any) error {
+	return c.injectInto(target, nil)
+}
+
+// Fills the tagged fields of the struct pointed to by target from the scope.
+func (s *Scope) InjectInto(target any) error {
+	return s.roids.injectInto(target, s)
+}
+
+// Gets the options keying the injector parameter of each field.
+func (si *structInjector) options() []ServiceOption {
+	opts := make([]ServiceOption, 0, len(si.fields))
+	for i, field := range si.fields {
+		if field.key != "" {
+			opts = append(opts, WithParamKey(i, field.key))
+		}
+		if field.optional {
+			opts = append(opts, withOptionalParam(i))
+		}
+		if field.group {
+			opts = append(opts, withGroupParam(i))
+		}
+	}
+	return opts
+}
+
+// Fills the tagged fields of the struct pointed to by target.
+func (c *RoidsContainer) injectInto(target any, scope *Scope) error {
+	targetVal := reflect.ValueOf(target)
+	if targetVal.Kind() != reflect.Pointer || targetVal.IsNil() || targetVal.Elem().Kind() != reflect.Struct {
+		return core.NewInjectorSignatureError(fmt.Errorf("%T is not a pointer to a struct", target), reflect.TypeOf(target))
+	}
+	structVal := targetVal.Elem()
+	fields, err := getInjectFields(structVal.Type())
+	if err != nil {
+		return core.NewInjectorSignatureError(err, structVal.Type())
+	}
+
+	for _, field := range fields {
+		fieldVal := structVal.Field(field.index)
+		if field.group {
+			c.servicesGraph.getOrAddGroup(fieldVal.Type().Elem())
+		}
+		param, err := c.lookupParam(fieldVal.Type(), field.key, field.optional)
+		if err != nil {
+			return core.NewInjectorSignatureError(err, structVal.Type())
+		}
+		if param.object != nil || param.factory != nil {
+			arg, err := param.arg(func(param *dependency) (reflect.Value, error) {
+				return c.resolveParam(param, scope)
+			})
+			if err != nil {
+				return err
+			}
+			fieldVal.Set(arg)
+			continue
+		}
+		if !param.service.registered() {
+			if param.optional {
+				// Optional fields keep their value.
+				continue
+			}
+			return core.NewServiceNotRegisteredError(param.service.SpecType)
+		}
+		dep, err := c.resolve(param.service, scope)
+		if err != nil {
+			return err
+		}
+		fieldVal.Set(param.value(dep))
+	}
+	return nil
+}
+
+// Gets the fields of the struct tagged to be injected.
+func getInjectFields(structType reflect.Type) ([]*injectField, error) {
+	if structType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%s is not a struct", structType)
+	}
+	var fields []*injectField
+	for i := 0; i < structType.NumField(); i++ {
+		structField := structType.Field(i)
+		tag, ok := structField.Tag.Lookup(injectTag)
+		if !ok {
+			continue
+		}
+		field, err := parseInjectTag(tag)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", structField.Name, err)
+		}
+		if field == nil {
+			continue
+		}
+		if !structField.IsExported() {
+			return nil, fmt.Errorf("field %s must be exported to be injected", structField.Name)
+		}
+		if err := checkGroupField(structField, field); err != nil {
+			return nil, err
+		}
+		field.index = i
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// Checks that a field injecting a group is an unkeyed slice of the group members.
+func checkGroupField(structField reflect.StructField, field *injectField) error {
+	if field.group && (structField.Type.Kind() != reflect.Slice || field.key != "") {
+		return fmt.Errorf("field %s must be an unkeyed slice to inject a group", structField.Name)
+	}
+	return nil
+}
+
+// Parses a `roids` struct tag. Returns nil if the tag does not mark the field to inject.
+func parseInjectTag(tag string) (*injectField, error) {
+	parts := strings.Split(tag, ",")
+	if strings.TrimSpace(parts[0]) != "inject" {
+		return nil, nil
+	}
+	return parseTagOptions(parts[1:])
+}
+
+// Parses the options of a `roids` struct tag: `optional`, `key=` and `group`.
+func parseTagOptions(parts []string) (*injectField, error) {
+	field := &injectField{}
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		switch {
+		case part == "":
+		case part == "optional":
+			field.optional = true
+		case part == "group":
+			field.group = true
+		case strings.HasPrefix(part, "key="):
+			field.key = strings.TrimPrefix(part, "key=")
+		default:
+			return nil, fmt.Errorf("unknown %s tag option '%s'", injectTag, part)
+		}
+	}
+	return field, nil
+}
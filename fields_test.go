@@ -0,0 +1,120 @@
+package roids_test
+
+import (
+	"testing"
+
+	"github.com/ShounakA/roids"
+	"github.com/ShounakA/roids/core"
+)
+
+type (
+	todoHandler struct {
+		Repo    ITodoRepository `roids:"inject"`
+		Replica IDbProvider     `roids:"inject,key=replica"`
+		Cache   ICache          `roids:"inject,optional"`
+		Name    string
+	}
+
+	selfHandler struct {
+		Self myInterface `roids:"inject"`
+	}
+
+	unexportedHandler struct {
+		repo ITodoRepository `roids:"inject"`
+	}
+
+	badTagHandler struct {
+		Repo ITodoRepository `roids:"inject,required"`
+	}
+)
+
+func (h *todoHandler) SameShape() string {
+	return "handler"
+}
+
+func (h *selfHandler) SameShape() string {
+	return "self"
+}
+
+func (h *unexportedHandler) SameShape() string {
+	return "unexported"
+}
+
+func (h *badTagHandler) SameShape() string {
+	return "bad"
+}
+
+func TestStruct(t *testing.T) {
+	c := roids.NewContainer()
+
+	if err := c.AddStaticService(new(myInterface), roids.Struct[todoHandler]()); err != nil {
+		t.Error("Should be able to add struct services.", err.Error())
+	}
+	_ = c.AddTransientService(new(ITodoRepository), func() *TodoRepository { return &TodoRepository{} })
+	_ = c.AddKeyedStaticService("replica", new(IDbProvider), newReplicaProvider)
+	if err := c.Build(); err != nil {
+		t.Error("Should build the container.", err.Error())
+	}
+
+	handler := roids.InjectFrom[myInterface](c).(*todoHandler)
+	if handler.Repo == nil {
+		t.Error("Should inject tagged fields.")
+	}
+	if handler.Replica.(*SqliteProvider).db != "replica" {
+		t.Error("Should inject keyed tagged fields.")
+	}
+	if handler.Cache != nil {
+		t.Error("Should leave optional fields without a registered service empty.")
+	}
+}
+
+func TestStruct_CircularDependency(t *testing.T) {
+	c := roids.NewContainer()
+
+	err := c.AddStaticService(new(myInterface), roids.Struct[selfHandler]())
+	if _, ok := err.(*core.CircularDependencyError); !ok {
+		t.Errorf("Expected CircularDependencyError, got %v", err)
+	}
+}
+
+func TestStruct_InvalidTags(t *testing.T) {
+	c := roids.NewContainer()
+
+	err := c.AddStaticService(new(myInterface), roids.Struct[unexportedHandler]())
+	if _, ok := err.(*core.InjectorError); !ok {
+		t.Errorf("Unexported fields cannot be injected, got %v", err)
+	}
+	err = c.AddStaticService(new(myInterface), roids.Struct[badTagHandler]())
+	if _, ok := err.(*core.InjectorError); !ok {
+		t.Errorf("Unknown tag options should be reported, got %v", err)
+	}
+}
+
+func TestInjectInto(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddTransientService(new(ITodoRepository), func() *TodoRepository { return &TodoRepository{} })
+	_ = c.AddStaticService(new(ICache), NewCache)
+	_ = c.Build()
+
+	handler := todoHandler{Name: "todo"}
+	err := c.InjectInto(&handler)
+	if _, ok := err.(*core.ServiceNotRegisteredError); !ok {
+		t.Errorf("Expected ServiceNotRegisteredError for the replica, got %v", err)
+	}
+
+	_ = c.AddKeyedStaticService("replica", new(IDbProvider), newReplicaProvider)
+	_ = c.Build()
+	if err := c.InjectInto(&handler); err != nil {
+		t.Error("Should inject into an existing value.", err.Error())
+	}
+	if handler.Repo == nil || handler.Replica == nil || handler.Cache != roids.InjectFrom[ICache](c) {
+		t.Error("Should inject every tagged field.")
+	}
+	if handler.Name != "todo" {
+		t.Error("Should not change untagged fields.")
+	}
+
+	if err := c.InjectInto(handler); err == nil {
+		t.Error("Should only inject into pointers to structs.")
+	}
+}
@@ -0,0 +1,47 @@
+package roids
+
+import (
+	"reflect"
+)
+
+// Gets the group service collecting members of the specification, adding it to the graph if needed.
+func (graph *serviceGraph) getOrAddGroup(specType reflect.Type) *Service {
+	groupType := reflect.SliceOf(specType)
+	if group := graph.getServiceByType(groupType); group != nil {
+		group.isGroup = true
+		return group
+	}
+	group := &Service{SpecType: groupType, isGroup: true}
+	_ = graph.addVertex(group)
+	return group
+}
+
+// True if unkeyed parameters of the type receive a group, even when it has no members.
+// Slices of concrete types can be specifications themselves, so they are only groups once a member is added.
+func isGroupType(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Interface
+}
+
+// Adds the member to the group, unless it was already added by a previous registration.
+func (graph *serviceGraph) addGroupMember(group *Service, member *Service) error {
+	for _, m := range group.members {
+		if m == member {
+			return nil
+		}
+	}
+	if err := graph.addEdgeKind(group, member, groupEdge); err != nil {
+		return err
+	}
+	group.members = append(group.members, member)
+	return nil
+}
+
+// Creates the slice of a group from the already resolved instances of its members.
+func newGroupInstance(group *Service, deps map[string]*any) *any {
+	instances := reflect.MakeSlice(group.SpecType, 0, len(group.members))
+	for _, member := range group.members {
+		instances = reflect.Append(instances, valueOf(*deps[member.Id], group.SpecType.Elem()))
+	}
+	instance := instances.Interface()
+	return &instance
+}
@@ -0,0 +1,99 @@
+package roids_test
+
+import (
+	"testing"
+
+	"github.com/ShounakA/roids"
+)
+
+type (
+	healthChecker interface {
+		Name() string
+	}
+
+	namedChecker struct {
+		name string
+	}
+
+	healthReport struct {
+		checkers []healthChecker
+	}
+)
+
+func (n *namedChecker) Name() string {
+	return n.name
+}
+
+func newDbChecker() *namedChecker {
+	return &namedChecker{name: "db"}
+}
+
+func newCacheChecker(cache ICache) *namedChecker {
+	return &namedChecker{name: "cache"}
+}
+
+func newQueueChecker() *namedChecker {
+	return &namedChecker{name: "queue"}
+}
+
+func newHealthReport(checkers []healthChecker) *healthReport {
+	return &healthReport{checkers: checkers}
+}
+
+func (h *healthReport) SameShape() string {
+	names := ""
+	for _, checker := range h.checkers {
+		names += checker.Name()
+	}
+	return names
+}
+
+func TestInGroup(t *testing.T) {
+	c := roids.NewContainer()
+
+	// Consumer is added before the members of the group.
+	if err := c.AddStaticService(new(myInterface), newHealthReport); err != nil {
+		t.Error("Should be able to add group dependencies.", err.Error())
+	}
+	if err := c.AddStaticService(new(healthChecker), newDbChecker, roids.InGroup()); err != nil {
+		t.Error("Should be able to add group members.", err.Error())
+	}
+	if err := c.AddTransientService(new(healthChecker), newCacheChecker, roids.InGroup()); err != nil {
+		t.Error("Should be able to add group members.", err.Error())
+	}
+	if err := c.AddKeyedStaticService("queue", new(healthChecker), newQueueChecker, roids.InGroup()); err != nil {
+		t.Error("Should be able to add keyed group members.", err.Error())
+	}
+	_ = c.AddStaticService(new(ICache), NewCache)
+	if err := c.Build(); err != nil {
+		t.Error("Should build the container.", err.Error())
+	}
+
+	if s := roids.InjectFrom[myInterface](c).SameShape(); s != "dbcachequeue" {
+		t.Errorf("Expected members in registration order 'dbcachequeue' but got %s", s)
+	}
+
+	checkers := roids.InjectFrom[[]healthChecker](c)
+	if len(checkers) != 3 {
+		t.Fatalf("Expected 3 group members but got %d", len(checkers))
+	}
+	if checkers[0] != roids.InjectFrom[[]healthChecker](c)[0] {
+		t.Error("Static group members should be shared.")
+	}
+	if checkers[2] != roids.InjectKeyedFrom[healthChecker](c, "queue") {
+		t.Error("Keyed group members should be injectable by key.")
+	}
+}
+
+func TestInGroup_Empty(t *testing.T) {
+	c := roids.NewContainer()
+
+	_ = c.AddStaticService(new(myInterface), newHealthReport)
+	if err := c.Build(); err != nil {
+		t.Error("Should build the container with an empty group.", err.Error())
+	}
+
+	if s := roids.InjectFrom[myInterface](c).SameShape(); s != "" {
+		t.Errorf("Expected an empty group but got %s", s)
+	}
+}
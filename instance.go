@@ -0,0 +1,57 @@
+package roids
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/ShounakA/roids/core"
+)
+
+// Adds an already created value to the global container as a static service of its specification.
+func AddInstance[T any](value T, opts ...ServiceOption) error {
+	return AddInstanceTo[T](GetRoids(), value, opts...)
+}
+
+// Adds an already created value to the provided container as a static service of its specification.
+// The value is injected as is: no injector is called, and it can be added after the container is built.
+// The container does not dispose the value, as it is owned by the caller.
+// `InGroup`, `OnStart` and `OnStop` can be used like for other static services.
+func AddInstanceTo[T any](c *RoidsContainer, value T, opts ...ServiceOption) (err error) {
+	specType := reflect.TypeOf(new(T)).Elem()
+	defer c.recordError(serviceKey{specType: specType}, &err)
+
+	instance := any(value)
+	if instance == nil {
+		return core.NewInjectorSignatureError(fmt.Errorf("instance of %s cannot be nil", specType), specType)
+	}
+
+	options := newServiceOptions(opts)
+	var group *Service
+	key := ""
+	if options.group {
+		group = c.servicesGraph.getOrAddGroup(specType)
+		key = fmt.Sprintf("group#%d", len(group.members))
+	}
+
+	service := &Service{SpecType: specType, Key: key}
+	if err := c.servicesGraph.addVertex(service); err != nil {
+		// A dependant added a placeholder vertex for the service, or it was registered before.
+		service = c.servicesGraph.getServiceByKey(specType, key)
+	}
+	service.Injector = nil
+	service.params = nil
+	service.implType = reflect.TypeOf(value)
+	service.lifetimeType = core.StaticLifetime
+	service.instance = &instance
+	service.created = true
+	service.provided = true
+	// A placeholder group without members is replaced by the provided slice.
+	service.isGroup = len(service.members) > 0
+	service.onStart = options.onStart
+	service.onStop = options.onStop
+
+	if group != nil {
+		return c.servicesGraph.addGroupMember(group, service)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package roids_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/ShounakA/roids"
+	"github.com/ShounakA/roids/core"
+)
+
+func TestAddInstance(t *testing.T) {
+	c := roids.NewContainer()
+	db := &SqliteProvider{db: "fixture"}
+	_ = c.AddTransientService(new(ITodoRepository), NewTodoRepository)
+
+	if err := roids.AddInstanceTo[IDbProvider](c, db); err != nil {
+		t.Error("Should add instances.", err.Error())
+	}
+	if err := roids.AddInstanceTo[ICache](c, NewCache()); err != nil {
+		t.Error("Should add instances.", err.Error())
+	}
+	if err := c.Build(); err != nil {
+		t.Error("Should build the container.", err.Error())
+	}
+
+	if roids.InjectFrom[IDbProvider](c) != db {
+		t.Error("Should inject the instance as is.")
+	}
+	if roids.InjectFrom[ITodoRepository](c).(*TodoRepository).db != db {
+		t.Error("Dependants should receive the instance.")
+	}
+}
+
+func TestAddInstance_AfterBuild(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.Build()
+
+	cache := NewCache()
+	if err := roids.AddInstanceTo[ICache](c, cache); err != nil {
+		t.Error("Should add instances after the container is built.", err.Error())
+	}
+	if impl, err := roids.InjectFromE[ICache](c); err != nil || impl != cache {
+		t.Errorf("Should inject instances added after the container is built, got %v", err)
+	}
+}
+
+func TestAddInstance_NotDisposed(t *testing.T) {
+	c := roids.NewContainer()
+	rec := newRecorder()
+	_ = roids.AddInstanceTo[IDbProvider](c, newClosingDb(rec))
+	_ = c.Build()
+
+	if err := c.Start(context.Background()); err != nil {
+		t.Error("Should start the instance.", err.Error())
+	}
+	if err := c.Shutdown(context.Background()); err != nil {
+		t.Error("Should shutdown the container.", err.Error())
+	}
+	if !reflect.DeepEqual(rec.Events(), []string{"start db", "stop db"}) {
+		t.Errorf("Instances should be started and stopped, but not disposed, got %v", rec.Events())
+	}
+	if roids.InjectFrom[IDbProvider](c) == nil {
+		t.Error("Instances should still be injected after shutdown.")
+	}
+}
+
+func TestAddInstance_Nil(t *testing.T) {
+	c := roids.NewContainer()
+	err := roids.AddInstanceTo[ICache](c, nil)
+	if _, ok := err.(*core.InjectorError); !ok {
+		t.Errorf("Expected InjectorError, got %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+package roids
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/ShounakA/roids/core"
+)
+
+// Calls the function with its parameters resolved from the global container. See `RoidsContainer.Invoke`.
+func Invoke(fn any) ([]any, error) {
+	return GetRoids().Invoke(fn)
+}
+
+// Calls the function with its parameters resolved from the container, and returns its results.
+// Parameters are resolved like injector parameters, so they can be factories, optional or parameter objects.
+// A trailing error returned by the function fails the call and is not part of the results.
+// The variadic parameter of a function is resolved like a slice parameter, e.g. `func(caches ...ICache)` receives the group of `ICache`.
+func (c *RoidsContainer) Invoke(fn any) ([]any, error) {
+	return c.invoke(fn, nil)
+}
+
+// Calls the function with its parameters resolved from the scope, and returns its results.
+func (s *Scope) Invoke(fn any) ([]any, error) {
+	return s.roids.invoke(fn, s)
+}
+
+// Calls the function with its parameters resolved from the container, or the scope when not nil.
+func (c *RoidsContainer) invoke(fn any, scope *Scope) ([]any, error) {
+	fnVal := reflect.ValueOf(fn)
+	if fnVal.Kind() != reflect.Func {
+		return nil, core.NewInjectorSignatureError(fmt.Errorf("%T is not a function", fn), reflect.TypeOf(fn))
+	}
+	if fnVal.IsNil() {
+		return nil, core.NewInjectorSignatureError(fmt.Errorf("%T is nil", fn), reflect.TypeOf(fn))
+	}
+	ftype := fnVal.Type()
+
+	params := make([]*dependency, ftype.NumIn())
+	for i := range params {
+		param, err := c.lookupParam(ftype.In(i), "", false)
+		if err != nil {
+			return nil, core.NewInjectorSignatureError(err, ftype)
+		}
+		params[i] = param
+	}
+	args, err := getArgs(params, func(param *dependency) (reflect.Value, error) {
+		return c.resolveParam(param, scope)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var values []reflect.Value
+	if ftype.IsVariadic() {
+		values = fnVal.CallSlice(args)
+	} else {
+		values = fnVal.Call(args)
+	}
+	if n := len(values); n > 0 && ftype.Out(n-1) == errorType {
+		if err := values[n-1]; !err.IsNil() {
+			return nil, err.Interface().(error)
+		}
+		values = values[:n-1]
+	}
+	results := make([]any, len(values))
+	for i, value := range values {
+		results[i] = value.Interface()
+	}
+	return results, nil
+}
+
+// Gets the argument of the parameter, resolving its service from the container, or the scope when not nil.
+func (c *RoidsContainer) resolveParam(param *dependency, scope *Scope) (reflect.Value, error) {
+	if param.factory != nil {
+		return c.newFactory(param, scope), nil
+	}
+	if param.optional && !param.service.registered() {
+		return param.empty(), nil
+	}
+	dep, err := c.resolve(param.service, scope)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return param.value(dep), nil
+}
+
+// Gets the dependency of a parameter of the type without adding it to the graph.
+// Parameter objects embedding `In` get a dependency for each of their fields.
+func (c *RoidsContainer) lookupParam(paramType reflect.Type, key string, optional bool) (*dependency, error) {
+	if isObject(paramType, inType) {
+		fields, err := getObjectFields(paramType, inType)
+		if err != nil {
+			return nil, err
+		}
+		param := &dependency{object: paramType, fields: make([]*dependency, len(fields))}
+		for i, field := range fields {
+			if field.group {
+				c.servicesGraph.getOrAddGroup(paramType.Field(field.index).Type.Elem())
+			}
+			fieldParam, err := c.lookupParam(paramType.Field(field.index).Type, field.key, field.optional)
+			if err != nil {
+				return nil, err
+			}
+			fieldParam.index = field.index
+			param.fields[i] = fieldParam
+		}
+		return param, nil
+	}
+
+	param, depType, _ := newDependency(paramType, optional)
+	param.service = c.servicesGraph.getServiceByKey(depType, key)
+	if param.service == nil && key == "" && isGroupType(depType) {
+		// Unkeyed slices of interfaces receive an empty group when there are no members.
+		param.service = c.servicesGraph.getOrAddGroup(depType.Elem())
+	}
+	if param.service == nil {
+		// Placeholder reporting that the service is not registered.
+		param.service = &Service{SpecType: depType, Key: key}
+	}
+	return param, nil
+}
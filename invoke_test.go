@@ -0,0 +1,128 @@
+package roids_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ShounakA/roids"
+	"github.com/ShounakA/roids/core"
+)
+
+func TestInvoke(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddStaticService(new(IDbProvider), NewSqliteProvider)
+	_ = c.AddStaticService(new(ICache), NewCache)
+	_ = c.AddTransientService(new(ITodoRepository), NewTodoRepository)
+	_ = c.Build()
+
+	results, err := c.Invoke(func(repo ITodoRepository, db roids.Optional[IDbProvider], missing roids.Optional[myInterface]) (ITodoRepository, int, error) {
+		if _, ok := db.Get(); !ok || missing.Present() {
+			return nil, 0, errors.New("unexpected optional dependencies")
+		}
+		return repo, 2, repo.DoStuff()
+	})
+	if err != nil {
+		t.Fatal("Should invoke the function with resolved parameters.", err.Error())
+	}
+	if len(results) != 2 {
+		t.Fatalf("Should return every result but the trailing error, got %v", results)
+	}
+	if _, ok := results[0].(ITodoRepository); !ok || results[1].(int) != 2 {
+		t.Errorf("Should return the results in order, got %v", results)
+	}
+}
+
+func TestInvoke_Error(t *testing.T) {
+	c := roids.NewContainer()
+	failure := errors.New("command failed")
+	_, err := c.Invoke(func() (int, error) {
+		return 0, failure
+	})
+	if !errors.Is(err, failure) {
+		t.Errorf("Should return the error of the function, got %v", err)
+	}
+}
+
+func TestInvoke_NotRegistered(t *testing.T) {
+	c := roids.NewContainer()
+	called := false
+	_, err := c.Invoke(func(db IDbProvider) {
+		called = true
+	})
+	if _, ok := err.(*core.ServiceNotRegisteredError); !ok {
+		t.Errorf("Expected ServiceNotRegisteredError, got %v", err)
+	}
+	if called {
+		t.Error("Should not call the function when a parameter cannot be resolved.")
+	}
+}
+
+func TestInvoke_NotAFunction(t *testing.T) {
+	c := roids.NewContainer()
+	if _, err := c.Invoke("main"); err == nil {
+		t.Error("Should not invoke values that are not functions.")
+	}
+	var fn func()
+	if _, err := c.Invoke(fn); err == nil {
+		t.Error("Should not invoke nil functions.")
+	}
+}
+
+func TestInvoke_Variadic(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddStaticService(new(ICache), NewCache, roids.InGroup())
+	_ = c.AddTransientService(new(ICache), NewCache, roids.InGroup())
+	_ = c.Build()
+
+	results, err := c.Invoke(func(caches ...ICache) int {
+		return len(caches)
+	})
+	if err != nil {
+		t.Fatal("Should invoke variadic functions.", err.Error())
+	}
+	if results[0].(int) != 2 {
+		t.Errorf("Should resolve the variadic parameter as a group, got %v", results)
+	}
+}
+
+func TestInvoke_VariadicWithoutMembers(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.Build()
+
+	results, err := c.Invoke(func(caches ...ICache) int {
+		return len(caches)
+	})
+	if err != nil {
+		t.Fatal("Should invoke variadic functions without group members.", err.Error())
+	}
+	if results[0].(int) != 0 {
+		t.Errorf("Should resolve the variadic parameter as an empty group, got %v", results)
+	}
+}
+
+func TestScope_Invoke(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddStaticService(new(ICache), NewCache)
+	_ = c.AddScopedService(new(iRequestLogger), newRequestLogger)
+	_ = c.Build()
+
+	scope := c.CreateScope()
+	defer scope.Close()
+	logger := roids.InjectFrom[iRequestLogger](scope)
+	results, err := scope.Invoke(func(params struct {
+		roids.In
+
+		Logger iRequestLogger
+	}) iRequestLogger {
+		return params.Logger
+	})
+	if err != nil {
+		t.Fatal("Should invoke the function from the scope.", err.Error())
+	}
+	if results[0] != logger {
+		t.Error("Should resolve scoped parameters from the scope.")
+	}
+	if _, err := c.Invoke(func(logger iRequestLogger) {}); err == nil {
+		t.Error("Should not resolve scoped parameters outside of a scope.")
+	}
+}
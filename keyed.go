@@ -0,0 +1,61 @@
+package roids
+
+import "github.com/ShounakA/roids/core"
+
+// Adds a static service registered under the key to the global container.
+// Keyed services are separate from the default registration of the same specification.
+func AddKeyedStaticService[T interface{}](key string, spec T, impl any, opts ...ServiceOption) error {
+	return GetRoids().AddKeyedStaticService(key, spec, impl, opts...)
+}
+
+// Adds a transient service registered under the key to the global container.
+func AddKeyedTransientService[T interface{}](key string, spec T, impl any, opts ...ServiceOption) error {
+	return GetRoids().AddKeyedTransientService(key, spec, impl, opts...)
+}
+
+// Adds a scoped service registered under the key to the global container.
+func AddKeyedScopedService[T interface{}](key string, spec T, impl any, opts ...ServiceOption) error {
+	return GetRoids().AddKeyedScopedService(key, spec, impl, opts...)
+}
+
+// Gets the implementation registered under the key from the global container.
+func InjectKeyed[T interface{}](key string) T {
+	return InjectKeyedFrom[T](GetRoids(), key)
+}
+
+// Gets the implementation registered under the key from the global container.
+// Returns an error instead of panicking when the service cannot be injected.
+func InjectKeyedE[T interface{}](key string) (T, error) {
+	return InjectKeyedFromE[T](GetRoids(), key)
+}
+
+// Adds a static service registered under the key to the container.
+// Use `WithParamKey` on a dependant service to inject it.
+func (c *RoidsContainer) AddKeyedStaticService(key string, spec any, impl any, opts ...ServiceOption) error {
+	return c.addService(key, spec, impl, core.StaticLifetime, opts)
+}
+
+// Adds a transient service registered under the key to the container.
+func (c *RoidsContainer) AddKeyedTransientService(key string, spec any, impl any, opts ...ServiceOption) error {
+	return c.addService(key, spec, impl, core.TransientLifetime, opts)
+}
+
+// Adds a scoped service registered under the key to the container.
+func (c *RoidsContainer) AddKeyedScopedService(key string, spec any, impl any, opts ...ServiceOption) error {
+	return c.addService(key, spec, impl, core.ScopedLifetime, opts)
+}
+
+// Gets the implementation registered under the key from the provided container or scope.
+func InjectKeyedFrom[T interface{}](r Resolver, key string) T {
+	impl, err := InjectKeyedFromE[T](r, key)
+	if err != nil {
+		panic(err)
+	}
+	return impl
+}
+
+// Gets the implementation registered under the key from the provided container or scope.
+// Returns an error instead of panicking when the service cannot be injected.
+func InjectKeyedFromE[T interface{}](r Resolver, key string) (T, error) {
+	return injectKeyed[T](r, key)
+}
@@ -0,0 +1,94 @@
+package roids_test
+
+import (
+	"testing"
+
+	"github.com/ShounakA/roids"
+	"github.com/ShounakA/roids/core"
+)
+
+type replicatedRepository struct {
+	primary IDbProvider
+	replica IDbProvider
+}
+
+func newReplicatedRepository(primary IDbProvider, replica IDbProvider) *replicatedRepository {
+	return &replicatedRepository{primary: primary, replica: replica}
+}
+
+func (r *replicatedRepository) DoStuff() error {
+	return nil
+}
+
+func newPrimaryProvider() *SqliteProvider {
+	return &SqliteProvider{db: "primary"}
+}
+
+func newReplicaProvider() *SqliteProvider {
+	return &SqliteProvider{db: "replica"}
+}
+
+func TestAddKeyedStaticService(t *testing.T) {
+	c := roids.NewContainer()
+
+	if err := c.AddKeyedStaticService("primary", new(IDbProvider), newPrimaryProvider); err != nil {
+		t.Error("Should be able to add keyed dependencies.", err.Error())
+	}
+	if err := c.AddKeyedStaticService("replica", new(IDbProvider), newReplicaProvider); err != nil {
+		t.Error("Should be able to add keyed dependencies.", err.Error())
+	}
+	if err := c.AddStaticService(new(IDbProvider), NewSqliteProvider); err != nil {
+		t.Error("Should be able to add the default dependency.", err.Error())
+	}
+	if err := c.Build(); err != nil {
+		t.Error("Should build the container.", err.Error())
+	}
+
+	primary := roids.InjectKeyedFrom[IDbProvider](c, "primary").(*SqliteProvider)
+	replica := roids.InjectKeyedFrom[IDbProvider](c, "replica").(*SqliteProvider)
+	def := roids.InjectFrom[IDbProvider](c).(*SqliteProvider)
+	if primary.db != "primary" || replica.db != "replica" || def.db != "test" {
+		t.Errorf("Keyed services should not overwrite each other. Got %s, %s and %s", primary.db, replica.db, def.db)
+	}
+
+	_, err := roids.InjectKeyedFromE[IDbProvider](c, "missing")
+	if _, ok := err.(*core.ServiceNotRegisteredError); !ok {
+		t.Errorf("Expected ServiceNotRegisteredError, got %v", err)
+	}
+}
+
+func TestWithParamKey(t *testing.T) {
+	c := roids.NewContainer()
+
+	// Dependant is added before its keyed dependencies.
+	err := c.AddTransientService(new(ITodoRepository), newReplicatedRepository,
+		roids.WithParamKey(0, "primary"), roids.WithParamKey(1, "replica"))
+	if err != nil {
+		t.Error("Should be able to add keyed parameters.", err.Error())
+	}
+	_ = c.AddKeyedStaticService("primary", new(IDbProvider), newPrimaryProvider)
+	_ = c.AddKeyedTransientService("replica", new(IDbProvider), newReplicaProvider)
+	if err := c.Build(); err != nil {
+		t.Error("Should build the container.", err.Error())
+	}
+
+	repo := roids.InjectFrom[ITodoRepository](c).(*replicatedRepository)
+	if repo.primary.(*SqliteProvider).db != "primary" {
+		t.Error("Should inject the primary provider into the first parameter.")
+	}
+	if repo.replica.(*SqliteProvider).db != "replica" {
+		t.Error("Should inject the replica provider into the second parameter.")
+	}
+	if repo.primary != roids.InjectKeyedFrom[IDbProvider](c, "primary") {
+		t.Error("Keyed static services should be shared.")
+	}
+}
+
+func TestWithParamKey_IndexOutOfRange(t *testing.T) {
+	c := roids.NewContainer()
+
+	err := c.AddStaticService(new(ITodoRepository), newReplicatedRepository, roids.WithParamKey(2, "replica"))
+	if _, ok := err.(*core.InjectorError); !ok {
+		t.Errorf("Expected InjectorError, got %v", err)
+	}
+}
@@ -0,0 +1,247 @@
+package roids
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/ShounakA/roids/core"
+)
+
+// Disposer is implemented by services that release resources when their container or scope ends.
+// Services implementing `io.Closer` are also disposed.
+type Disposer interface {
+	Dispose(ctx context.Context) error
+}
+
+// Starter is implemented by static services that run work when the container is started.
+type Starter interface {
+	Start(ctx context.Context) error
+}
+
+// Stopper is implemented by static services that stop their work when the container is stopped.
+type Stopper interface {
+	Stop(ctx context.Context) error
+}
+
+// Hook is a lifecycle action registered with `OnStart` or `OnStop`. Receives the static instance of the service.
+type Hook func(ctx context.Context, instance any) error
+
+// Starts every built static service of the global container.
+func Start(ctx context.Context) error {
+	return GetRoids().Start(ctx)
+}
+
+// Stops every started static service of the global container.
+func Stop(ctx context.Context) error {
+	return GetRoids().Stop(ctx)
+}
+
+// Starts every built static service in topological order, so dependencies are started before their dependants.
+// Runs `Starter.Start` and then the `OnStart` hooks of each service.
+// If a service fails to start, the services already started are stopped again.
+func (c *RoidsContainer) Start(ctx context.Context) error {
+	c.Logger.Debug("Starting static services:")
+	started := instanceSet{}
+	for _, service := range c.started {
+		started.add(*service.instance)
+	}
+	for _, service := range c.getBuiltStatics() {
+		if service.started || !started.add(*service.instance) {
+			continue
+		}
+		c.Logger.Debug(fmt.Sprintf("Starting static service %s:%s", service.ID(), service.SpecType.String()))
+		if err := c.runHooks(ctx, service, startHooks(service)); err != nil {
+			c.Logger.Debug(err.Error())
+			// Roll back even when the start context is done.
+			return errors.Join(err, c.Stop(context.WithoutCancel(ctx)))
+		}
+		service.started = true
+		c.started = append(c.started, service)
+	}
+	return nil
+}
+
+// Stops every started static service in reverse start order.
+// Runs `Stopper.Stop` and then the `OnStop` hooks of each service.
+// Returns every hook error joined together.
+func (c *RoidsContainer) Stop(ctx context.Context) error {
+	c.Logger.Debug("Stopping static services:")
+	var errs []error
+	for i := len(c.started) - 1; i >= 0; i-- {
+		service := c.started[i]
+		c.Logger.Debug(fmt.Sprintf("Stopping static service %s:%s", service.ID(), service.SpecType.String()))
+		if err := c.runHooks(ctx, service, stopHooks(service)); err != nil {
+			errs = append(errs, err)
+		}
+		service.started = false
+	}
+	c.started = nil
+	return errors.Join(errs...)
+}
+
+// Disposes every built static service of the global container.
+func Shutdown(ctx context.Context) error {
+	return GetRoids().Shutdown(ctx)
+}
+
+// Disposes every built static service in reverse instantiation order, so dependants are always
+// disposed before their dependencies. Started services are stopped first. Stops when the context is done.
+// Instances added with `AddInstance` are not disposed. The container can be built again afterwards.
+// Returns every disposal error joined together.
+func (c *RoidsContainer) Shutdown(ctx context.Context) error {
+	var errs []error
+	if len(c.started) > 0 {
+		errs = append(errs, c.Stop(ctx))
+	}
+
+	c.Logger.Debug("Disposing static services:")
+	services := c.getBuiltStatics()
+	disposed := instanceSet{}
+	for i := len(services) - 1; i >= 0; i-- {
+		service := services[i]
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		if service.provided {
+			// Instances added with `AddInstance` are disposed by their owner.
+			continue
+		}
+		var err error
+		if disposed.add(*service.instance) {
+			c.Logger.Debug(fmt.Sprintf("Disposing static service %s:%s", service.ID(), service.SpecType.String()))
+			err = dispose(ctx, *service.instance)
+		}
+		service.resetStatic()
+		if err != nil {
+			errs = append(errs, core.NewDisposeError(err, service.SpecType))
+		}
+	}
+	// Aliases resolve the instance of their target again when the container is built again.
+	for _, alias := range c.getStatics() {
+		if alias.aliasOf != nil && !alias.aliasOf.created {
+			alias.resetStatic()
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Instances already started or disposed, so that an instance returned for several services,
+// e.g. by an injector returning several services, is only started and disposed once.
+type instanceSet map[any]struct{}
+
+// Adds the instance to the set. Returns false if it was already added.
+// Only pointers are tracked, other instances are always added.
+func (set instanceSet) add(instance any) bool {
+	if reflect.ValueOf(instance).Kind() != reflect.Pointer {
+		return true
+	}
+	if _, ok := set[instance]; ok {
+		return false
+	}
+	set[instance] = struct{}{}
+	return true
+}
+
+// Gets every built static service in instantiation order.
+// Aliases are skipped, as their instance belongs to the service they were added with.
+func (c *RoidsContainer) getBuiltStatics() []*Service {
+	var services []*Service
+	for _, service := range c.getStatics() {
+		if service.created && service.aliasOf == nil {
+			services = append(services, service)
+		}
+	}
+	return services
+}
+
+// Gets every static service in instantiation order.
+func (c *RoidsContainer) getStatics() []*Service {
+	order := c.servicesGraph.getInstantiationOrder()
+	services := make([]*Service, 0, order.GetSize())
+	for order.GetSize() > 0 {
+		service, _ := c.servicesGraph.getVertex(*order.Pop())
+		if service.lifetimeType == core.StaticLifetime {
+			services = append(services, service)
+		}
+	}
+	return services
+}
+
+// Gets the start hooks of the service, starting with `Starter.Start`.
+func startHooks(service *Service) []Hook {
+	hooks := make([]Hook, 0, len(service.onStart)+1)
+	if _, ok := (*service.instance).(Starter); ok {
+		hooks = append(hooks, func(ctx context.Context, instance any) error {
+			return instance.(Starter).Start(ctx)
+		})
+	}
+	return append(hooks, service.onStart...)
+}
+
+// Gets the stop hooks of the service, starting with `Stopper.Stop`.
+func stopHooks(service *Service) []Hook {
+	hooks := make([]Hook, 0, len(service.onStop)+1)
+	if _, ok := (*service.instance).(Stopper); ok {
+		hooks = append(hooks, func(ctx context.Context, instance any) error {
+			return instance.(Stopper).Stop(ctx)
+		})
+	}
+	return append(hooks, service.onStop...)
+}
+
+// Runs the hooks of the service in order, each with its own timeout. Stops at the first failing hook.
+func (c *RoidsContainer) runHooks(ctx context.Context, service *Service, hooks []Hook) error {
+	for _, hook := range hooks {
+		hookCtx, cancel := withHookTimeout(ctx, c.hookTimeout)
+		err := runWithContext(hookCtx, func() error {
+			return hook(hookCtx, *service.instance)
+		})
+		cancel()
+		if err != nil {
+			return core.NewHookError(err, service.SpecType)
+		}
+	}
+	return nil
+}
+
+// Adds the timeout to the context, unless it is zero.
+func withHookTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// Disposes the instance if it implements `Disposer` or `io.Closer`.
+// Returns the context error if the context is done before the instance is disposed.
+func dispose(ctx context.Context, instance any) error {
+	var disposeFunc func() error
+	switch d := instance.(type) {
+	case Disposer:
+		disposeFunc = func() error { return d.Dispose(ctx) }
+	case io.Closer:
+		disposeFunc = d.Close
+	default:
+		return nil
+	}
+	return runWithContext(ctx, disposeFunc)
+}
+
+// Runs the function, returning early with the context error if the context is done first.
+func runWithContext(ctx context.Context, fn func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- fn()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,268 @@
+package roids_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ShounakA/roids"
+	"github.com/ShounakA/roids/core"
+)
+
+type (
+	iRecorder interface {
+		Record(event string)
+		Events() []string
+	}
+
+	recorder struct {
+		events []string
+	}
+
+	closingDb struct {
+		rec iRecorder
+		err error
+	}
+
+	disposingRepo struct {
+		db  IDbProvider
+		rec iRecorder
+	}
+
+	blockingCache struct{}
+)
+
+func newRecorder() *recorder {
+	return &recorder{}
+}
+
+func (r *recorder) Record(event string) {
+	r.events = append(r.events, event)
+}
+
+func (r *recorder) Events() []string {
+	return r.events
+}
+
+func newClosingDb(rec iRecorder) *closingDb {
+	return &closingDb{rec: rec}
+}
+
+func (d *closingDb) Start(ctx context.Context) error {
+	d.rec.Record("start db")
+	return nil
+}
+
+func (d *closingDb) Stop(ctx context.Context) error {
+	d.rec.Record("stop db")
+	return nil
+}
+
+func (d *closingDb) Close() error {
+	d.rec.Record("close db")
+	return d.err
+}
+
+func newDisposingRepo(db IDbProvider, rec iRecorder) *disposingRepo {
+	return &disposingRepo{db: db, rec: rec}
+}
+
+func (r *disposingRepo) DoStuff() error {
+	return nil
+}
+
+func (r *disposingRepo) Dispose(ctx context.Context) error {
+	r.rec.Record("dispose repo")
+	return nil
+}
+
+func newBlockingCache() *blockingCache {
+	return &blockingCache{}
+}
+
+func (b *blockingCache) Delete(k string) {}
+
+func (b *blockingCache) Close() error {
+	time.Sleep(time.Second)
+	return nil
+}
+
+func newLifecycleContainer(t *testing.T) *roids.RoidsContainer {
+	c := roids.NewContainer()
+	_ = c.AddStaticService(new(iRecorder), newRecorder)
+	_ = c.AddStaticService(new(IDbProvider), newClosingDb)
+	_ = c.AddStaticService(new(ITodoRepository), newDisposingRepo)
+	if err := c.Build(); err != nil {
+		t.Error("Should build the container.", err.Error())
+	}
+	return c
+}
+
+func TestShutdown(t *testing.T) {
+	c := newLifecycleContainer(t)
+	rec := roids.InjectFrom[iRecorder](c)
+
+	if err := c.Shutdown(context.Background()); err != nil {
+		t.Error("Should dispose all services.", err.Error())
+	}
+	events := rec.Events()
+	if len(events) != 2 || events[0] != "dispose repo" || events[1] != "close db" {
+		t.Errorf("Dependants should be disposed before their dependencies. Got %v", events)
+	}
+
+	if err := c.Shutdown(context.Background()); err != nil || len(rec.Events()) != 2 {
+		t.Error("Services should only be disposed once.")
+	}
+}
+
+func TestShutdown_Rebuild(t *testing.T) {
+	c := newLifecycleContainer(t)
+	_ = c.Provide(NewCache, roids.As[ICache]())
+	_ = c.Build()
+	db := roids.InjectFrom[IDbProvider](c)
+	cache := roids.InjectFrom[ICache](c)
+
+	if err := c.Shutdown(context.Background()); err != nil {
+		t.Fatal("Should dispose all services.", err.Error())
+	}
+	if err := c.Build(); err != nil {
+		t.Fatal("Should build the container again after shutting down.", err.Error())
+	}
+	rebuilt, err := roids.InjectFromE[IDbProvider](c)
+	if err != nil {
+		t.Fatal("Should inject the rebuilt static services.", err.Error())
+	}
+	if rebuilt == db {
+		t.Error("Should construct new static instances after shutting down.")
+	}
+	if alias := roids.InjectFrom[ICache](c); alias == cache || alias != roids.InjectFrom[*MyCache](c) {
+		t.Error("Aliases should resolve the rebuilt instance.")
+	}
+}
+
+func TestShutdown_AggregatesErrors(t *testing.T) {
+	c := newLifecycleContainer(t)
+	rec := roids.InjectFrom[iRecorder](c)
+	errClose := errors.New("close failed")
+	roids.InjectFrom[IDbProvider](c).(*closingDb).err = errClose
+
+	err := c.Shutdown(context.Background())
+	var disposeErr *core.DisposeError
+	if !errors.As(err, &disposeErr) || !errors.Is(err, errClose) {
+		t.Errorf("Expected DisposeError wrapping the close error, got %v", err)
+	}
+	if len(rec.Events()) != 2 {
+		t.Error("Should keep disposing after an error.")
+	}
+}
+
+func TestShutdown_ContextDeadline(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddStaticService(new(ICache), newBlockingCache)
+	_ = c.Build()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := c.Shutdown(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Should stop when the deadline is exceeded, got %v", err)
+	}
+}
+
+func recordHook(event string, err error) roids.Hook {
+	return func(ctx context.Context, instance any) error {
+		instance.(*disposingRepo).rec.Record(event)
+		return err
+	}
+}
+
+func TestStart(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddStaticService(new(iRecorder), newRecorder)
+	_ = c.AddStaticService(new(ITodoRepository), newDisposingRepo,
+		roids.OnStart(recordHook("start repo", nil)), roids.OnStop(recordHook("stop repo", nil)))
+	_ = c.AddStaticService(new(IDbProvider), newClosingDb)
+	if err := c.Build(); err != nil {
+		t.Error("Should build the container.", err.Error())
+	}
+	rec := roids.InjectFrom[iRecorder](c)
+
+	if err := c.Start(context.Background()); err != nil {
+		t.Error("Should start all services.", err.Error())
+	}
+	if err := c.Start(context.Background()); err != nil {
+		t.Error("Should not start services twice.", err.Error())
+	}
+	if err := c.Stop(context.Background()); err != nil {
+		t.Error("Should stop all services.", err.Error())
+	}
+
+	expected := []string{"start db", "start repo", "stop repo", "stop db"}
+	events := rec.Events()
+	if len(events) != len(expected) {
+		t.Fatalf("Expected events %v but got %v", expected, events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Errorf("Expected events %v but got %v", expected, events)
+		}
+	}
+}
+
+func TestStart_Clear(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddStaticService(new(iRecorder), newRecorder)
+	_ = c.AddStaticService(new(IDbProvider), newClosingDb)
+	if err := c.Build(); err != nil {
+		t.Error("Should build the container.", err.Error())
+	}
+	rec := roids.InjectFrom[iRecorder](c)
+	if err := c.Start(context.Background()); err != nil {
+		t.Error("Should start all services.", err.Error())
+	}
+
+	c.Clear()
+	if err := c.Stop(context.Background()); err != nil {
+		t.Error("Should stop the cleared container.", err.Error())
+	}
+	if events := rec.Events(); len(events) != 1 || events[0] != "start db" {
+		t.Errorf("Cleared services should not be stopped, got %v", events)
+	}
+}
+
+func TestStart_RollsBackOnFailure(t *testing.T) {
+	errStart := errors.New("start failed")
+	c := roids.NewContainer()
+	_ = c.AddStaticService(new(iRecorder), newRecorder)
+	_ = c.AddStaticService(new(IDbProvider), newClosingDb)
+	_ = c.AddStaticService(new(ITodoRepository), newDisposingRepo,
+		roids.OnStart(recordHook("start repo", errStart)), roids.OnStop(recordHook("stop repo", nil)))
+	_ = c.Build()
+	rec := roids.InjectFrom[iRecorder](c)
+
+	err := c.Start(context.Background())
+	var hookErr *core.HookError
+	if !errors.As(err, &hookErr) || !errors.Is(err, errStart) {
+		t.Errorf("Expected HookError wrapping the start error, got %v", err)
+	}
+	events := rec.Events()
+	if len(events) != 3 || events[2] != "stop db" {
+		t.Errorf("Started dependencies should be stopped again. Got %v", events)
+	}
+}
+
+func TestStart_HookTimeout(t *testing.T) {
+	c := roids.NewContainer(roids.WithHookTimeout(10 * time.Millisecond))
+	_ = c.AddStaticService(new(ICache), NewCache, roids.OnStart(func(ctx context.Context, instance any) error {
+		<-ctx.Done()
+		time.Sleep(time.Second)
+		return nil
+	}))
+	_ = c.Build()
+
+	err := c.Start(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Should stop waiting for the hook after its timeout, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package roids
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/ShounakA/roids/core"
+)
+
+// In is embedded in a parameter object to inject each of its exported fields, instead of the object itself.
+// Fields are tagged with the same options as struct injection, e.g. `roids:"optional"`, `roids:"key=replica"` or `roids:"group"`.
+type In struct{}
+
+// Out is embedded in a result object returned by an injector to add each of its exported fields as its own service.
+// Fields can be tagged with `roids:"key=replica"` or `roids:"group"`.
+type Out struct{}
+
+// Types of the markers embedded in parameter and result objects.
+var (
+	inType  = reflect.TypeOf(In{})
+	outType = reflect.TypeOf(Out{})
+)
+
+// True if the type is a struct embedding the marker.
+func isObject(t reflect.Type, marker reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.Anonymous && field.Type == marker {
+			return true
+		}
+	}
+	return false
+}
+
+// Gets the fields of a parameter or result object, other than its marker.
+// Fields tagged with `roids:"-"` are skipped.
+func getObjectFields(objectType reflect.Type, marker reflect.Type) ([]*injectField, error) {
+	var fields []*injectField
+	for i := 0; i < objectType.NumField(); i++ {
+		structField := objectType.Field(i)
+		if structField.Anonymous && structField.Type == marker {
+			continue
+		}
+		tag := structField.Tag.Get(injectTag)
+		if tag == "-" {
+			continue
+		}
+		if !structField.IsExported() {
+			return nil, fmt.Errorf("field %s of %s must be exported", structField.Name, objectType)
+		}
+		// Every field is injected, so the inject option is not required.
+		field, err := parseTagOptions(strings.Split(strings.TrimPrefix(tag, "inject"), ","))
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", structField.Name, err)
+		}
+		if marker == outType && (field.optional || (field.group && field.key != "")) {
+			return nil, fmt.Errorf("field %s of %s can only be keyed or added to a group", structField.Name, objectType)
+		}
+		if marker == inType {
+			if err := checkGroupField(structField, field); err != nil {
+				return nil, err
+			}
+		}
+		field.index = i
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// Adds a service for each field of the result object returned by the injector of the service.
+// The fields share the construction and the lifetime of the service.
+func (c *RoidsContainer) addResults(srcService *Service, resultType reflect.Type) error {
+	fields, err := getObjectFields(resultType, outType)
+	if err != nil {
+		return core.NewInjectorSignatureError(err, srcService.SpecType)
+	}
+	for _, field := range fields {
+		index := field.index
+		fieldType := resultType.Field(index).Type
+
+		var group *Service
+		key := field.key
+		if field.group {
+			group = c.servicesGraph.getOrAddGroup(fieldType)
+			key = fmt.Sprintf("group#%d", len(group.members))
+		}
+		result, err := c.addDerived(srcService, fieldType, key, func(object reflect.Value) reflect.Value {
+			return object.Field(index)
+		})
+		if err != nil {
+			return err
+		}
+		if group != nil {
+			if err := c.servicesGraph.addGroupMember(group, result); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package roids_test
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ShounakA/roids"
+	"github.com/ShounakA/roids/core"
+)
+
+type (
+	repoParams struct {
+		roids.In
+
+		Db      IDbProvider
+		Replica IDbProvider     `roids:"key=replica"`
+		Caches  []ICache        `roids:"group"`
+		Tracer  ITodoRepository `roids:"optional"`
+	}
+
+	paramRepo struct {
+		params repoParams
+	}
+
+	dbResults struct {
+		roids.Out
+
+		Db      IDbProvider
+		Replica IDbProvider `roids:"key=replica"`
+		Cache   ICache      `roids:"group"`
+	}
+
+	unexportedParams struct {
+		roids.In
+
+		db IDbProvider
+	}
+)
+
+func (r *paramRepo) SameShape() string {
+	return "params"
+}
+
+func TestIn(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddStaticService(new(IDbProvider), NewSqliteProvider)
+	_ = c.AddKeyedStaticService("replica", new(IDbProvider), newReplicaProvider)
+	_ = c.AddStaticService(new(ICache), NewCache, roids.InGroup())
+	_ = c.AddTransientService(new(ICache), NewCache, roids.InGroup())
+	err := c.AddTransientService(new(myInterface), func(params repoParams) *paramRepo {
+		return &paramRepo{params: params}
+	})
+	if err != nil {
+		t.Error("Should add injectors taking parameter objects.", err.Error())
+	}
+	if err := c.Validate(); err != nil {
+		t.Error("Should validate the fields of parameter objects.", err.Error())
+	}
+	_ = c.Build()
+
+	params := roids.InjectFrom[myInterface](c).(*paramRepo).params
+	if params.Db != roids.InjectFrom[IDbProvider](c) {
+		t.Error("Should inject the fields of parameter objects.")
+	}
+	if params.Replica.(*SqliteProvider).db != "replica" {
+		t.Error("Should inject keyed fields.")
+	}
+	if len(params.Caches) != 2 {
+		t.Errorf("Should inject every member of the group, got %d", len(params.Caches))
+	}
+	if params.Tracer != nil {
+		t.Error("Should leave optional fields empty.")
+	}
+}
+
+func TestIn_MissingField(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddStaticService(new(IDbProvider), NewSqliteProvider)
+	_ = c.AddStaticService(new(myInterface), func(params repoParams) *paramRepo {
+		return &paramRepo{params: params}
+	})
+
+	var missing *core.ServiceNotRegisteredError
+	errs := unwrapJoined(c.Validate())
+	if len(errs) != 1 || !errors.As(errs[0], &missing) || missing.SpecType.Name() != "IDbProvider" {
+		t.Errorf("Should report the missing keyed field, got %v", errs)
+	}
+
+	err := c.AddStaticService(new(myInterface), func(params unexportedParams) *paramRepo { return nil })
+	if _, ok := err.(*core.InjectorError); !ok {
+		t.Errorf("Expected InjectorError for unexported fields, got %v", err)
+	}
+}
+
+func TestOut(t *testing.T) {
+	c := roids.NewContainer()
+	var calls atomic.Int32
+	err := c.Provide(func() (dbResults, error) {
+		calls.Add(1)
+		return dbResults{Db: NewSqliteProvider(), Replica: newReplicaProvider(), Cache: NewCache()}, nil
+	})
+	if err != nil {
+		t.Error("Should add injectors returning result objects.", err.Error())
+	}
+	_ = c.AddStaticService(new(ICache), NewCache, roids.InGroup())
+	if err := c.Build(); err != nil {
+		t.Error("Should build the container.", err.Error())
+	}
+
+	if roids.InjectFrom[IDbProvider](c).(*SqliteProvider).db != "test" {
+		t.Error("Should add each field as a service.")
+	}
+	if roids.InjectKeyedFrom[IDbProvider](c, "replica").(*SqliteProvider).db != "replica" {
+		t.Error("Should add keyed fields.")
+	}
+	if len(roids.InjectFrom[[]ICache](c)) != 2 {
+		t.Error("Should add group fields to the group.")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Fields should share one construction, got %d", calls.Load())
+	}
+}
@@ -0,0 +1,52 @@
+package roids
+
+import (
+	"reflect"
+)
+
+// Injector parameter holding the service of type T if it is registered, and empty otherwise.
+// Services that are registered but fail to construct are still reported as errors.
+type Optional[T any] struct {
+	value   T
+	present bool
+}
+
+// Parameter types wrapping a service that may not be registered, like `Optional`.
+type optional interface {
+	// Type of the wrapped service.
+	optionalOf() reflect.Type
+	// Creates the wrapper holding the instance.
+	newOptional(instance any) any
+}
+
+// Type of the optional interface, used to detect optional injector parameters.
+var optionalType = reflect.TypeOf((*optional)(nil)).Elem()
+
+// Gets the service, and true if it is registered.
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.present
+}
+
+// True if the service is registered.
+func (o Optional[T]) Present() bool {
+	return o.present
+}
+
+func (Optional[T]) optionalOf() reflect.Type {
+	return reflect.TypeOf(new(T)).Elem()
+}
+
+func (Optional[T]) newOptional(instance any) any {
+	// Injectors can return a nil interface, which is wrapped as the zero value.
+	value, _ := instance.(T)
+	return Optional[T]{value: value, present: true}
+}
+
+// Gets the type of the service wrapped by the injector parameter type.
+// Returns the parameter type itself if it is not optional.
+func optionalOf(paramType reflect.Type) (reflect.Type, bool) {
+	if !paramType.Implements(optionalType) {
+		return paramType, false
+	}
+	return reflect.Zero(paramType).Interface().(optional).optionalOf(), true
+}
@@ -0,0 +1,95 @@
+package roids_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ShounakA/roids"
+)
+
+type (
+	tracedRepo struct {
+		tracer roids.Optional[ICache]
+	}
+
+	tracedHandler struct {
+		Tracer roids.Optional[ICache] `roids:"inject"`
+	}
+)
+
+func (r *tracedRepo) DoStuff() error {
+	return nil
+}
+
+func (h *tracedHandler) SameShape() string {
+	return "traced"
+}
+
+func newTracedRepo(tracer roids.Optional[ICache]) *tracedRepo {
+	return &tracedRepo{tracer: tracer}
+}
+
+func TestOptional_NotRegistered(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddStaticService(new(ITodoRepository), newTracedRepo)
+	_ = c.AddKeyedTransientService("transient", new(ITodoRepository), newTracedRepo)
+
+	if err := c.Validate(); err != nil {
+		t.Error("Optional dependencies do not need to be registered.", err.Error())
+	}
+	if err := c.Build(); err != nil {
+		t.Error("Should build without optional dependencies.", err.Error())
+	}
+	if roids.InjectFrom[ITodoRepository](c).(*tracedRepo).tracer.Present() {
+		t.Error("Optional dependencies should be empty when not registered.")
+	}
+	if roids.InjectKeyedFrom[ITodoRepository](c, "transient").(*tracedRepo).tracer.Present() {
+		t.Error("Optional dependencies should be empty when not registered.")
+	}
+}
+
+func TestOptional_Registered(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddStaticService(new(ITodoRepository), newTracedRepo)
+	_ = c.AddKeyedTransientService("transient", new(ITodoRepository), newTracedRepo)
+	_ = c.AddStaticService(new(ICache), NewCache)
+	_ = c.Build()
+
+	cache := roids.InjectFrom[ICache](c)
+	for _, repo := range []ITodoRepository{roids.InjectFrom[ITodoRepository](c), roids.InjectKeyedFrom[ITodoRepository](c, "transient")} {
+		if tracer, ok := repo.(*tracedRepo).tracer.Get(); !ok || tracer != cache {
+			t.Error("Optional dependencies should hold the registered service.")
+		}
+	}
+}
+
+func TestOptional_ConstructionError(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddTransientService(new(ITodoRepository), func(db roids.Optional[IDbProvider]) *TodoRepository {
+		return &TodoRepository{}
+	})
+	_ = c.AddTransientService(new(IDbProvider), newFailingSqliteProvider)
+	_ = c.Build()
+
+	if _, err := roids.InjectFromE[ITodoRepository](c); !errors.Is(err, errConnectionRefused) {
+		t.Errorf("Registered optional dependencies should still report errors, got %v", err)
+	}
+}
+
+func TestOptional_Field(t *testing.T) {
+	c := roids.NewContainer()
+	var handler tracedHandler
+	if err := c.InjectInto(&handler); err != nil || handler.Tracer.Present() {
+		t.Errorf("Optional fields should be empty when not registered, got %v", err)
+	}
+
+	_ = c.AddStaticService(new(ICache), NewCache)
+	_ = c.AddStaticService(new(myInterface), roids.Struct[tracedHandler]())
+	_ = c.Build()
+	if err := c.InjectInto(&handler); err != nil || !handler.Tracer.Present() {
+		t.Errorf("Optional fields should hold the registered service, got %v", err)
+	}
+	if !roids.InjectFrom[myInterface](c).(*tracedHandler).Tracer.Present() {
+		t.Error("Optional fields of struct services should hold the registered service.")
+	}
+}
@@ -0,0 +1,146 @@
+package roids
+
+import (
+	"log/slog"
+	"reflect"
+	"time"
+)
+
+// Functional option used to configure a container created with `NewContainer`.
+type ContainerOption func(*RoidsContainer)
+
+// Sets the logger used by the container. Defaults to a debug logger writing to roids.log
+func WithLogger(logger *slog.Logger) ContainerOption {
+	return func(c *RoidsContainer) {
+		c.Logger = logger
+	}
+}
+
+// Sets the timeout of each start and stop hook. Hooks are only bound by the `Start` and `Stop` context by default.
+func WithHookTimeout(timeout time.Duration) ContainerOption {
+	return func(c *RoidsContainer) {
+		c.hookTimeout = timeout
+	}
+}
+
+// Only constructs static services on first injection, or first construction of a dependant, instead of in `Build`.
+func WithLazyStatics() ContainerOption {
+	return func(c *RoidsContainer) {
+		c.lazy = true
+	}
+}
+
+// Constructs independent static services concurrently in `Build`, using at most the number of workers.
+func WithParallelBuild(workers int) ContainerOption {
+	return func(c *RoidsContainer) {
+		c.workers = workers
+	}
+}
+
+// Sets the policy for static services capturing dependencies of the lifetime, either `core.TransientLifetime` or `core.ScopedLifetime`.
+// Static services are constructed outside of any scope, so allowing scoped dependencies only silences the report.
+func WithCaptivePolicy(lifetime string, policy CaptivePolicy) ContainerOption {
+	return func(c *RoidsContainer) {
+		c.captivePolicies[lifetime] = policy
+	}
+}
+
+// Functional option used to configure a service when it is added to a container.
+type ServiceOption func(*serviceOptions)
+
+// Options collected from the `ServiceOption`s of a service.
+type serviceOptions struct {
+	// Keys of the services to inject, by injector parameter index.
+	paramKeys map[int]string
+	// Injector parameters that receive the zero value when their service is not registered.
+	optionalParams map[int]bool
+	// Injector parameters receiving a group, even when it has no members.
+	groupParams map[int]bool
+	// True if the service is a member of the group of its specification.
+	group bool
+	// True if the static service is only constructed on first use.
+	lazy bool
+	// Hooks run when the container is started.
+	onStart []Hook
+	// Hooks run when the container is stopped.
+	onStop []Hook
+	// Additional specifications resolving to the same instance as the service.
+	as []reflect.Type
+	// Lifetime of services added with `Provide` or `AddFactory`.
+	lifetime string
+}
+
+// Injects the service registered with the key into the injector parameter at the index.
+func WithParamKey(index int, key string) ServiceOption {
+	return func(o *serviceOptions) {
+		o.paramKeys[index] = key
+	}
+}
+
+// Injects the zero value into the injector parameter at the index when its service is not registered.
+func withOptionalParam(index int) ServiceOption {
+	return func(o *serviceOptions) {
+		o.optionalParams[index] = true
+	}
+}
+
+// Injects the group of the slice element type into the injector parameter at the index, even when it has no members.
+func withGroupParam(index int) ServiceOption {
+	return func(o *serviceOptions) {
+		o.groupParams[index] = true
+	}
+}
+
+// Adds the service to the group of its specification.
+// Injecting a slice of the specification gives every member of the group in registration order.
+func InGroup() ServiceOption {
+	return func(o *serviceOptions) {
+		o.group = true
+	}
+}
+
+// Only constructs the static service on first injection, or first construction of a dependant, instead of in `Build`.
+func WithLazy() ServiceOption {
+	return func(o *serviceOptions) {
+		o.lazy = true
+	}
+}
+
+// Runs the hook with the static instance of the service when the container is started.
+func OnStart(hook Hook) ServiceOption {
+	return func(o *serviceOptions) {
+		o.onStart = append(o.onStart, hook)
+	}
+}
+
+// Runs the hook with the static instance of the service when the container is stopped.
+func OnStop(hook Hook) ServiceOption {
+	return func(o *serviceOptions) {
+		o.onStop = append(o.onStop, hook)
+	}
+}
+
+// Adds a specification of type T resolving to the same instance as the service.
+// The implementation must be assignable to T. Lifetime, hooks and disposal only apply to the service itself.
+func As[T any]() ServiceOption {
+	return func(o *serviceOptions) {
+		o.as = append(o.as, reflect.TypeOf(new(T)).Elem())
+	}
+}
+
+// Sets the lifetime of a service added with `Provide` or `AddFactory`: `core.StaticLifetime`, `core.TransientLifetime` or `core.ScopedLifetime`.
+// Static by default.
+func WithLifetime(lifetime string) ServiceOption {
+	return func(o *serviceOptions) {
+		o.lifetime = lifetime
+	}
+}
+
+// Applies the service options in order.
+func newServiceOptions(opts []ServiceOption) *serviceOptions {
+	options := &serviceOptions{paramKeys: make(map[int]string), optionalParams: make(map[int]bool), groupParams: make(map[int]bool)}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
@@ -0,0 +1,143 @@
+package roids
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/ShounakA/roids/core"
+)
+
+// Injector parameter building a new instance of T from the container on each call.
+// The dependency on T is recorded in the graph, but T is only constructed when the provider is called.
+type Provider[T any] struct {
+	get func() (any, error)
+}
+
+// Injector parameter building T from the container once, on first access.
+// Every copy of the parameter shares the same instance.
+type Lazy[T any] struct {
+	state *lazyState
+}
+
+// Instance shared by the copies of a `Lazy`.
+type lazyState struct {
+	once     sync.Once
+	get      func() (any, error)
+	instance any
+	err      error
+}
+
+// Injector parameter types resolving the service they wrap on demand, like `Provider` and `Lazy`.
+type factory interface {
+	// Type of the service resolved by the factory.
+	factoryOf() reflect.Type
+	// Creates the factory, resolving the service with get.
+	newFactory(get func() (any, error)) any
+}
+
+// Type of the factory interface, used to detect injector parameters resolved on demand.
+var factoryType = reflect.TypeOf((*factory)(nil)).Elem()
+
+// Builds a new instance of T. Panics if it cannot be constructed.
+func (p Provider[T]) Get() T {
+	impl, err := p.GetE()
+	if err != nil {
+		panic(err)
+	}
+	return impl
+}
+
+// Builds a new instance of T. Returns an error instead of panicking when it cannot be constructed.
+func (p Provider[T]) GetE() (T, error) {
+	return getFactory[T](p.get)
+}
+
+// Gets the instance of T, building it on first access. Panics if it cannot be constructed.
+func (l Lazy[T]) Get() T {
+	impl, err := l.GetE()
+	if err != nil {
+		panic(err)
+	}
+	return impl
+}
+
+// Gets the instance of T, building it on first access.
+// Returns an error instead of panicking when it cannot be constructed. The error is returned on every access.
+func (l Lazy[T]) GetE() (T, error) {
+	if l.state == nil {
+		return getFactory[T](nil)
+	}
+	return getFactory[T](func() (any, error) {
+		l.state.once.Do(func() {
+			l.state.instance, l.state.err = l.state.get()
+		})
+		return l.state.instance, l.state.err
+	})
+}
+
+func (Provider[T]) factoryOf() reflect.Type {
+	return reflect.TypeOf(new(T)).Elem()
+}
+
+func (Provider[T]) newFactory(get func() (any, error)) any {
+	return Provider[T]{get: get}
+}
+
+func (Lazy[T]) factoryOf() reflect.Type {
+	return reflect.TypeOf(new(T)).Elem()
+}
+
+func (Lazy[T]) newFactory(get func() (any, error)) any {
+	return Lazy[T]{state: &lazyState{get: get}}
+}
+
+// Gets the instance of T resolved by get. Factories that were not injected have nothing to resolve.
+func getFactory[T any](get func() (any, error)) (T, error) {
+	var impl T
+	if get == nil {
+		return impl, core.NewServiceNotRegisteredError(reflect.TypeOf(new(T)).Elem())
+	}
+	instance, err := get()
+	if err != nil {
+		return impl, err
+	}
+	// Injectors can return a nil interface, which is resolved as the zero value.
+	impl, _ = instance.(T)
+	return impl, nil
+}
+
+// Gets the type of the service resolved by the injector parameter type.
+// Returns the parameter type itself if it is not a factory.
+func factoryOf(paramType reflect.Type) (reflect.Type, bool) {
+	if !paramType.Implements(factoryType) {
+		return paramType, false
+	}
+	return reflect.Zero(paramType).Interface().(factory).factoryOf(), true
+}
+
+// Creates the factory parameter, resolving its service from the container, or the scope when not nil.
+// Deferred parameters fail instead of recursing into the construction of their dependant.
+// As they do not order construction, they construct static services that are not built yet.
+func (c *RoidsContainer) newFactory(param *dependency, scope *Scope) reflect.Value {
+	var deferred deferredFactory
+	get := func() (any, error) {
+		if deferred != nil && param.dependant != nil {
+			if deferred.constructing().Load() && c.servicesGraph.dependsOn(param.service, param.dependant) {
+				return nil, core.NewDeferredRecursionError(param.dependant.SpecType, param.service.SpecType)
+			}
+			if param.service.lifetimeType == core.StaticLifetime {
+				if err := c.constructStatic(param.service); err != nil {
+					return nil, err
+				}
+			}
+		}
+		dep, err := c.resolve(param.service, scope)
+		if err != nil {
+			return nil, err
+		}
+		return *dep, nil
+	}
+	instance := reflect.Zero(param.factory).Interface().(factory).newFactory(get)
+	deferred, _ = instance.(deferredFactory)
+	return reflect.ValueOf(instance)
+}
@@ -0,0 +1,151 @@
+package roids_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ShounakA/roids"
+	"github.com/ShounakA/roids/core"
+)
+
+type (
+	repoFactory struct {
+		repos roids.Provider[ITodoRepository]
+		cache roids.Lazy[ICache]
+	}
+
+	iRepoFactory interface {
+		NewRepo() ITodoRepository
+		Cache() ICache
+	}
+)
+
+func newRepoFactory(repos roids.Provider[ITodoRepository], cache roids.Lazy[ICache]) *repoFactory {
+	return &repoFactory{repos: repos, cache: cache}
+}
+
+func (f *repoFactory) NewRepo() ITodoRepository {
+	return f.repos.Get()
+}
+
+func (f *repoFactory) Cache() ICache {
+	return f.cache.Get()
+}
+
+func TestProvider_NewInstanceOnEachCall(t *testing.T) {
+	c := roids.NewContainer()
+	var repos, caches atomic.Int32
+	_ = c.AddStaticService(new(iRepoFactory), newRepoFactory)
+	_ = c.AddTransientService(new(ITodoRepository), func() *TodoRepository {
+		repos.Add(1)
+		return &TodoRepository{}
+	})
+	_ = c.AddTransientService(new(ICache), func() *MyCache {
+		caches.Add(1)
+		return NewCache()
+	})
+	if err := c.Build(); err != nil {
+		t.Error("Should build the container.", err.Error())
+	}
+	if repos.Load() != 0 || caches.Load() != 0 {
+		t.Error("Should not construct the services before they are used.")
+	}
+
+	factory := roids.InjectFrom[iRepoFactory](c)
+	if factory.NewRepo() == factory.NewRepo() || repos.Load() != 2 {
+		t.Error("Provider should build a new instance on each call.")
+	}
+	if factory.Cache() != factory.Cache() || caches.Load() != 1 {
+		t.Error("Lazy should build the instance once, on first access.")
+	}
+}
+
+func TestProvider_ServiceNotRegistered(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddStaticService(new(iRepoFactory), newRepoFactory)
+	_ = c.AddTransientService(new(ITodoRepository), func() *TodoRepository { return &TodoRepository{} })
+	if err := c.Build(); err != nil {
+		t.Error("Missing services of a factory are only reported on use.", err.Error())
+	}
+
+	factory := roids.InjectFrom[iRepoFactory](c).(*repoFactory)
+	if _, err := factory.cache.GetE(); err == nil {
+		t.Error("Expected an error for the unregistered cache.")
+	} else if _, ok := err.(*core.ServiceNotRegisteredError); !ok {
+		t.Errorf("Expected ServiceNotRegisteredError, got %T", err)
+	}
+
+	var empty roids.Provider[ICache]
+	if _, err := empty.GetE(); err == nil {
+		t.Error("Providers that were not injected should report an error.")
+	}
+}
+
+func TestProvider_CircularDependency(t *testing.T) {
+	c := roids.NewContainer()
+	err := c.AddStaticService(new(ITodoRepository), func(repos roids.Provider[ITodoRepository]) *TodoRepository {
+		return &TodoRepository{}
+	})
+	if _, ok := err.(*core.CircularDependencyError); !ok {
+		t.Errorf("Provider edges should be checked for cycles, got %v", err)
+	}
+}
+
+func TestProvider_ResolvesFromScope(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddTransientService(new(iRepoFactory), newRepoFactory)
+	_ = c.AddTransientService(new(ITodoRepository), func() *TodoRepository { return &TodoRepository{} })
+	_ = c.AddScopedService(new(ICache), NewCache)
+	_ = c.Build()
+
+	scope := c.CreateScope()
+	defer scope.Close()
+	factory := roids.InjectFrom[iRepoFactory](scope)
+	if factory.Cache() != roids.InjectFrom[ICache](scope) {
+		t.Error("Factories injected from a scope should resolve scoped services from it.")
+	}
+}
+
+func TestInjectInto_Provider(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddTransientService(new(ITodoRepository), func() *TodoRepository { return &TodoRepository{} })
+	_ = c.Build()
+
+	var handler struct {
+		Repos roids.Provider[ITodoRepository] `roids:"inject"`
+	}
+	if err := c.InjectInto(&handler); err != nil {
+		t.Error("Should inject providers into fields.", err.Error())
+	}
+	if handler.Repos.Get() == nil {
+		t.Error("Should build the service from the injected provider.")
+	}
+}
+
+func TestProvider_ScopedConstructorResolvesFromScope(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddScopedService(new(ICache), NewCache)
+	_ = c.AddScopedService(new(iRepoFactory), func(repos roids.Provider[ITodoRepository], cache roids.Lazy[ICache]) *repoFactory {
+		// Resolves another scoped service while the scope constructs this one.
+		cache.Get()
+		return newRepoFactory(repos, cache)
+	})
+	_ = c.Build()
+
+	scope := c.CreateScope()
+	defer scope.Close()
+	done := make(chan error, 1)
+	go func() {
+		_, err := roids.InjectFromE[iRepoFactory](scope)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Error("Should construct the scoped service.", err.Error())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Scoped constructors resolving other scoped services should not deadlock.")
+	}
+}
@@ -0,0 +1,58 @@
+package roids
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// True if the injector returns several services, optionally followed by an error.
+func hasMultipleResults(ftype reflect.Type) bool {
+	results := ftype.NumOut()
+	if results > 0 && ftype.Out(results-1) == errorType {
+		results--
+	}
+	return results > 1
+}
+
+// Wraps an injector returning several services into an injector returning a result object embedding `Out`,
+// with a field for each service. The trailing error of the injector, if any, is returned as is.
+func newResultsInjector(injector any) any {
+	ftype := reflect.TypeOf(injector)
+	results := ftype.NumOut()
+	failable := ftype.Out(results-1) == errorType
+	if failable {
+		results--
+	}
+
+	fields := []reflect.StructField{{Name: outType.Name(), Type: outType, Anonymous: true}}
+	for i := 0; i < results; i++ {
+		fields = append(fields, reflect.StructField{Name: fmt.Sprintf("Result%d", i), Type: ftype.Out(i)})
+	}
+	resultType := reflect.StructOf(fields)
+
+	in := make([]reflect.Type, ftype.NumIn())
+	for i := range in {
+		in[i] = ftype.In(i)
+	}
+	out := []reflect.Type{resultType}
+	if failable {
+		out = append(out, errorType)
+	}
+	wrapper := reflect.MakeFunc(reflect.FuncOf(in, out, false), func(args []reflect.Value) []reflect.Value {
+		values := reflect.ValueOf(injector).Call(args)
+		result := reflect.New(resultType).Elem()
+		if failable {
+			if err := values[results]; !err.IsNil() {
+				return []reflect.Value{result, err}
+			}
+		}
+		for i := 0; i < results; i++ {
+			result.Field(i + 1).Set(values[i])
+		}
+		if failable {
+			return []reflect.Value{result, reflect.Zero(errorType)}
+		}
+		return []reflect.Value{result}
+	})
+	return wrapper.Interface()
+}
@@ -0,0 +1,132 @@
+package roids_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/ShounakA/roids"
+	"github.com/ShounakA/roids/core"
+)
+
+type (
+	// Reads and writes a pipe, closed once.
+	pipe struct {
+		closes int
+	}
+
+	pipeUser struct {
+		reader io.Reader
+		writer io.Writer
+	}
+)
+
+func (p *pipe) Read(b []byte) (int, error) {
+	return 0, io.EOF
+}
+
+func (p *pipe) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (p *pipe) Close() error {
+	p.closes++
+	return nil
+}
+
+func TestProvide_MultipleResults(t *testing.T) {
+	c := roids.NewContainer()
+	calls := 0
+	err := c.Provide(func(cache ICache) (io.Reader, io.Writer, error) {
+		calls++
+		p := &pipe{}
+		return p, p, nil
+	})
+	if err != nil {
+		t.Fatal("Should add injectors returning several services.", err.Error())
+	}
+	_ = c.AddStaticService(new(ICache), NewCache)
+	if err := c.Validate(); err != nil {
+		t.Error("Should validate injectors returning several services.", err.Error())
+	}
+	if err := c.Build(); err != nil {
+		t.Fatal("Should build injectors returning several services.", err.Error())
+	}
+
+	reader, err := roids.InjectFromE[io.Reader](c)
+	if err != nil {
+		t.Fatal("Should inject the first result.", err.Error())
+	}
+	writer, err := roids.InjectFromE[io.Writer](c)
+	if err != nil {
+		t.Fatal("Should inject the second result.", err.Error())
+	}
+	if calls != 1 {
+		t.Errorf("Results should share one construction, constructed %d times.", calls)
+	}
+	if reader.(*pipe) != writer.(*pipe) {
+		t.Error("Results should come from the same construction.")
+	}
+
+	if err := c.Shutdown(context.Background()); err != nil {
+		t.Error("Should shut down the results.", err.Error())
+	}
+	if closes := reader.(*pipe).closes; closes != 1 {
+		t.Errorf("An instance returned for several services should be closed once, closed %d times.", closes)
+	}
+}
+
+func TestProvide_MultipleResultsTransient(t *testing.T) {
+	c := roids.NewContainer()
+	calls := 0
+	_ = c.Provide(func() (io.Reader, io.Writer) {
+		calls++
+		p := &pipe{}
+		return p, p
+	}, roids.WithLifetime(core.TransientLifetime))
+	_ = c.Provide(func(reader io.Reader, writer io.Writer) *pipeUser {
+		return &pipeUser{reader: reader, writer: writer}
+	}, roids.WithLifetime(core.TransientLifetime))
+	_ = c.Build()
+
+	user, err := roids.InjectFromE[*pipeUser](c)
+	if err != nil {
+		t.Fatal("Should inject a dependant of both results.", err.Error())
+	}
+	if calls != 1 || user.reader.(*pipe) != user.writer.(*pipe) {
+		t.Errorf("Results should share one construction per resolution, constructed %d times.", calls)
+	}
+	_, _ = roids.InjectFromE[*pipeUser](c)
+	if calls != 2 {
+		t.Errorf("Transient results should be constructed on each resolution, constructed %d times.", calls)
+	}
+}
+
+func TestProvide_MultipleResultsError(t *testing.T) {
+	c := roids.NewContainer()
+	failure := errors.New("cannot open pipe")
+	_ = c.Provide(func() (io.Reader, io.Writer, error) {
+		return nil, nil, failure
+	})
+
+	err := c.Build()
+	var constructionErr *core.ConstructionError
+	if !errors.As(err, &constructionErr) {
+		t.Fatalf("Build should report a ConstructionError, got %v", err)
+	}
+	if !errors.Is(err, failure) {
+		t.Error("ConstructionError should wrap the injector error.")
+	}
+}
+
+func TestAddStaticService_MultipleResults(t *testing.T) {
+	c := roids.NewContainer()
+	err := c.AddStaticService(new(io.Reader), func() (io.Reader, io.Writer) {
+		p := &pipe{}
+		return p, p
+	})
+	if _, ok := err.(*core.InjectorError); !ok {
+		t.Errorf("Should only add injectors returning several services with Provide, got %v", err)
+	}
+}
@@ -4,7 +4,8 @@
  */
 
 // Package containing custom dependency container for dependency injection.
-// There is only ever one container and it can be used globally to access all the dependencies.
+// A global container can be used to access all the dependencies, and independent
+// containers can be created with `NewContainer`.
 package roids
 
 import (
@@ -20,45 +21,135 @@ import (
 )
 
 // Thread-safe function to get the global instance of the dependency container.
-func GetRoids() *roidsContainer {
+func GetRoids() *RoidsContainer {
 	once.Do(func() {
 		globalRoidsContainer = newRoidsContainer(nil)
 	})
 	return globalRoidsContainer
 }
 
-// Builds all static services in container.
+// Creates a new, independent instance of the dependency container.
+// Services registered in one container are never visible to another,
+// so multiple applications, parallel tests and libraries can each own their graph.
+func NewContainer(opts ...ContainerOption) *RoidsContainer {
+	return newRoidsContainer(nil, opts...)
+}
+
+// Builds all static services in the global container.
 func Build() error {
-	roids := GetRoids()
+	return GetRoids().Build()
+}
+
+// Clears the global container of all services
+// SUPER UNSAFE. Only used during testing. Dont use while running an application.
+func UNSAFE_Clear() {
+	GetRoids().Clear()
+}
+
+// Builds all static services in container.
+// Fails without constructing any service if a static service captures a dependency rejected by the `CaptivePolicy`.
+func (c *RoidsContainer) Build() error {
 	startTime := time.Now()
-	roids.Logger.Debug("Building static services:")
-	order := roids.servicesGraph.getInstantiationOrder()
-	roids.Logger.Debug(order.String())
+	if errs := c.getRejectedCaptives(); len(errs) > 0 {
+		c.Logger.Debug(errs[0].Error())
+		return errs[0]
+	}
+	if c.workers > 1 {
+		c.Logger.Debug(fmt.Sprintf("Building static services with %d workers:", c.workers))
+		if err := c.buildParallel(); err != nil {
+			c.Logger.Debug(err.Error())
+			return err
+		}
+		c.Logger.Debug(fmt.Sprintf("Completed building all services in %dµs", time.Since(startTime).Microseconds()))
+		return nil
+	}
+	c.Logger.Debug("Building static services:")
+	order := c.servicesGraph.getInstantiationOrder()
+	c.Logger.Debug(order.String())
 	for order.GetSize() > 0 {
 		vertexId := *order.Pop()
-		service, _ := roids.servicesGraph.getVertex(vertexId)
-		roids.Logger.Debug(fmt.Sprintf("Building static service %s:%s", service.ID(), service.SpecType.String()))
-		if service.lifetimeType == core.StaticLifetime {
-			if service.isRoot && !service.created {
-				roids.Logger.Debug("Creating leaf service...")
-				setStaticLeafDep(service)
-			} else if !service.isRoot && !service.created {
-				roids.Logger.Debug("Creating branch service...")
-				setStaticBranchDep(service)
-			} else {
-				return core.NewUnknownError(nil)
+		service, _ := c.servicesGraph.getVertex(vertexId)
+		c.Logger.Debug(fmt.Sprintf("Building static service %s:%s", service.ID(), service.SpecType.String()))
+		if service.lifetimeType == core.StaticLifetime && !c.isLazy(service) {
+			if err := c.constructStatic(service); err != nil {
+				c.Logger.Debug(err.Error())
+				return err
 			}
 		}
 	}
-	roids.Logger.Debug(fmt.Sprintf("Completed building all services in %dµs", time.Since(startTime).Microseconds()))
+	c.Logger.Debug(fmt.Sprintf("Completed building all services in %dµs", time.Since(startTime).Microseconds()))
 	return nil
 }
 
-// Clears the container of all services
-// SUPER UNSAFE. Only used during testing. Dont use while running an application.
-func UNSAFE_Clear() {
-	roids := GetRoids()
-	roids.servicesGraph.clearGraph()
+// Builds the static services one layer at a time. Services in a layer are independent,
+// so they are constructed concurrently by a bounded pool of workers.
+// Reports the same error as the sequential build: the first failure in instantiation order.
+// Once a service fails, only the services instantiated before it in that order are still built.
+func (c *RoidsContainer) buildParallel() error {
+	positions := make(map[string]int)
+	order := c.servicesGraph.getInstantiationOrder()
+	for i := 0; order.GetSize() > 0; i++ {
+		positions[*order.Pop()] = i
+	}
+
+	var mu sync.Mutex
+	var firstErr error
+	firstFailure := len(positions)
+	// True if the sequential build would construct the service before the first failure so far.
+	beforeFailure := func(service *Service) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return positions[service.Id] < firstFailure
+	}
+
+	for _, layer := range c.servicesGraph.getInstantiationLayers() {
+		services := make([]*Service, 0, len(layer))
+		for _, vertexId := range layer {
+			service, _ := c.servicesGraph.getVertex(vertexId)
+			if service.lifetimeType == core.StaticLifetime && !c.isLazy(service) {
+				services = append(services, service)
+			}
+		}
+
+		jobs := make(chan *Service)
+		var wg sync.WaitGroup
+		for w := 0; w < min(c.workers, len(services)); w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for service := range jobs {
+					if !beforeFailure(service) {
+						continue
+					}
+					c.Logger.Debug(fmt.Sprintf("Building static service %s:%s", service.ID(), service.SpecType.String()))
+					if err := c.constructStatic(service); err != nil {
+						mu.Lock()
+						if positions[service.Id] < firstFailure {
+							firstFailure, firstErr = positions[service.Id], err
+						}
+						mu.Unlock()
+					}
+				}
+			}()
+		}
+		for _, service := range services {
+			if beforeFailure(service) {
+				jobs <- service
+			}
+		}
+		close(jobs)
+		wg.Wait()
+	}
+	return firstErr
+}
+
+// Clears the container of all services.
+// Instances already injected from the container are not affected.
+func (c *RoidsContainer) Clear() {
+	c.servicesGraph.clearGraph()
+	c.registrationErrs = nil
+	// The cleared services are not stopped by `Stop`.
+	c.started = nil
 }
 
 /**
@@ -66,143 +157,280 @@ func UNSAFE_Clear() {
  */
 
 // Application wide globalRoidsContainer of the dependency container.
-var globalRoidsContainer *roidsContainer
+var globalRoidsContainer *RoidsContainer
 
 // Atomic boolean to ensure that the container is only created once.
 var once sync.Once
 
+// Logger shared by every container that was not given one.
+var defaultLogger *slog.Logger
+
+// Atomic boolean to ensure that the default log file is only created once.
+var loggerOnce sync.Once
+
+// Gets the default library logger, writing to roids.log
+func getDefaultLogger() *slog.Logger {
+	loggerOnce.Do(func() {
+		logFile, _ := os.Create("roids.log")
+		defaultLogger = slog.New(slog.NewTextHandler(logFile, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	})
+	return defaultLogger
+}
+
 // Build a new instance of the specified service.
-func buildTransientDep(service *Service) *any {
-	roids := GetRoids()
-	roids.Logger.Debug(fmt.Sprintf("Building transient service %s:%s", service.ID(), service.SpecType.String()))
-	hist := roids.servicesGraph.getServiceOrderById(service.Id)
-	deps := make(map[reflect.Type]*any)
-	roids.Logger.Debug(hist.String())
+// Scoped dependencies are reused from the scope when one is provided.
+func (c *RoidsContainer) buildTransientDep(service *Service, scope *Scope) (*any, error) {
+	c.Logger.Debug(fmt.Sprintf("Building transient service %s:%s", service.ID(), service.SpecType.String()))
+	hist := c.servicesGraph.getServiceOrderById(service.Id)
+	deps := make(map[string]*any)
+	required := make(map[string]bool)
+	service.collectRequired(required, scope)
+	c.Logger.Debug(hist.String())
 	for hist.GetSize() > 0 {
 		id := *hist.Pop()
-		service, err := roids.servicesGraph.getVertex(id)
+		if !required[id] {
+			continue
+		}
+		service, err := c.servicesGraph.getVertex(id)
 		if err != nil {
 			log.Panicf("Should have the vertex in the graph")
 		}
-		roids.Logger.Debug(fmt.Sprintf("Fetching dependant service %s:%s", service.ID(), service.SpecType.String()))
+		c.Logger.Debug(fmt.Sprintf("Fetching dependant service %s:%s", service.ID(), service.SpecType.String()))
 		switch service.lifetimeType {
 		case core.StaticLifetime:
-			deps[service.SpecType] = service.instance
+			staticService, err := c.getStatic(service)
+			if err != nil {
+				return nil, err
+			}
+			deps[service.Id] = staticService
 		case core.TransientLifetime:
-			if service.isRoot {
-				roids.Logger.Debug("Creating leaf service...")
-				transService := createTransientLeafDep(service)
-				deps[service.SpecType] = transService
-			} else {
-				roids.Logger.Debug("Creating branch service...")
-				transService := createTransientBranchDep(service, deps)
-				deps[service.SpecType] = transService
+			c.Logger.Debug("Creating transient service...")
+			// Services without dependencies can still resolve factory and deferred parameters from the scope.
+			transService, err := c.createTransientBranchDep(service, deps, scope)
+			if err != nil {
+				return nil, err
+			}
+			deps[service.Id] = transService
+		case core.ScopedLifetime:
+			if scope == nil {
+				return nil, core.NewScopedServiceError(service.SpecType)
+			}
+			c.Logger.Debug("Fetching scoped service...")
+			scopedService, err := scope.getOrCreate(service, deps)
+			if err != nil {
+				return nil, err
+			}
+			deps[service.Id] = scopedService
+		default:
+			// Missing dependencies are reported by their dependants, unless they are optional.
+			if service.isGroup {
+				c.Logger.Debug("Collecting group members...")
+				deps[service.Id] = newGroupInstance(service, deps)
 			}
 		}
 	}
 
-	transientDep := deps[service.SpecType]
-	return transientDep
+	transientDep := deps[service.Id]
+	return transientDep, nil
 }
 
 // Get all deps before using injector.
-func getArgsForFunction(service *Service) []reflect.Value {
-	roids := GetRoids()
-	roids.Logger.Debug("Injecting services from injector function")
-	injected := service.Injector
-	injectedVal := reflect.ValueOf(injected)
-	injectedType := injectedVal.Type()
-
-	argValues := make([]reflect.Value, injectedType.NumIn())
-
-	// Get the type of each argument
-	for i := 0; i < injectedType.NumIn(); i++ {
-		serviceType := injectedType.In(i)
-		service := roids.servicesGraph.getServiceByType(serviceType)
-		if service.lifetimeType == core.StaticLifetime {
-			roids.Logger.Debug(fmt.Sprintf("Injecting static service %s:%s", service.ID(), service.SpecType.String()))
-			instanceVal := reflect.ValueOf(*(service.instance))
-			argValues[i] = instanceVal
-		} else {
-			roids.Logger.Debug(fmt.Sprintf("Injecting transient service %s:%s", service.ID(), service.SpecType.String()))
-			dep := buildTransientDep(service)
-			instanceVal := reflect.ValueOf(*dep)
-			argValues[i] = instanceVal
-		}
-	}
-	return argValues
-}
-
-// Creates a new leaf instance of the specified service
-func createTransientLeafDep(service *Service) *any {
-	injector := service.Injector
-	injectorVal := reflect.ValueOf(injector)
-	results := injectorVal.Call(nil)
-	leafDep := results[0].Interface()
-	return &leafDep
+func (c *RoidsContainer) getArgsForFunction(params []*dependency) ([]reflect.Value, error) {
+	c.Logger.Debug("Injecting services from injector function")
+
+	// Get the service of each argument.
+	// Static services live outside of any scope, so they cannot depend on scoped services.
+	return getArgs(params, func(param *dependency) (reflect.Value, error) {
+		c.Logger.Debug(fmt.Sprintf("Injecting service %s:%s", param.service.ID(), param.service.String()))
+		if param.factory != nil {
+			return c.newFactory(param, nil), nil
+		}
+		if param.optional && !param.service.registered() {
+			return param.empty(), nil
+		}
+		dep, err := c.resolve(param.service, nil)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return param.value(dep), nil
+	})
 }
 
-// Creates a new branch or root instance of the specified service
-func createTransientBranchDep(service *Service, deps map[reflect.Type]*any) *any {
-	injectorVal := reflect.ValueOf(service.Injector)
-	injectorType := injectorVal.Type()
+// Gets the argument of each parameter, resolving the service of each dependency with resolve.
+func getArgs(params []*dependency, resolve func(*dependency) (reflect.Value, error)) ([]reflect.Value, error) {
+	argValues := make([]reflect.Value, len(params))
+	for i, param := range params {
+		arg, err := param.arg(resolve)
+		if err != nil {
+			return nil, err
+		}
+		argValues[i] = arg
+	}
+	return argValues, nil
+}
 
-	argValues := make([]reflect.Value, injectorType.NumIn())
-	for i := 0; i < injectorType.NumIn(); i++ {
-		serviceType := injectorType.In(i)
-		dep := deps[serviceType]
-		instanceVal := reflect.ValueOf(*dep)
-		argValues[i] = instanceVal
+// Gets the instance of a static service. Lazy services are constructed on first use.
+func (c *RoidsContainer) getStatic(service *Service) (*any, error) {
+	if c.isLazy(service) {
+		if err := c.constructStatic(service); err != nil {
+			return nil, err
+		}
 	}
-	results := injectorVal.Call(argValues)
-	dep := results[0].Interface()
-	return &dep
+	if !service.created {
+		return nil, core.NewContainerNotBuiltError(service.SpecType)
+	}
+	return service.instance, nil
 }
 
-// Sets a static instance of a leaf service.
-// These services should not have parameters in there injector functions.
-// Meaning they can be created by calling the injector.
-func setStaticLeafDep(service *Service) {
-	service.instance = createTransientLeafDep(service)
-	service.created = true
+// Constructs the static instance of the service only once, even when called concurrently.
+// Returns the construction error to every caller if it failed.
+func (c *RoidsContainer) constructStatic(service *Service) error {
+	if service.provided {
+		return nil
+	}
+	// Lazy services are constructed outside of `Build`, so their captive dependencies are checked here as well.
+	if errs := c.rejectCaptives(service); len(errs) > 0 {
+		return errs[0]
+	}
+	return service.guard.do(func() error {
+		c.Logger.Debug("Creating static service...")
+		return c.setStaticBranchDep(service)
+	})
+}
+
+// True if the static service is only constructed on first use.
+func (c *RoidsContainer) isLazy(service *Service) bool {
+	return c.lazy || service.lazy
+}
+
+// Gets an instance of the service for the provided scope.
+// A nil scope means the service is injected directly from the container.
+func (c *RoidsContainer) resolve(service *Service, scope *Scope) (*any, error) {
+	switch service.lifetimeType {
+	case core.StaticLifetime:
+		return c.getStatic(service)
+	case core.TransientLifetime:
+	case core.ScopedLifetime:
+		if scope == nil {
+			return nil, core.NewScopedServiceError(service.SpecType)
+		}
+	default:
+		if !service.registered() {
+			// Only a placeholder vertex was added for the service by one of its dependants.
+			return nil, core.NewServiceNotRegisteredError(service.SpecType)
+		}
+	}
+	return c.buildTransientDep(service, scope)
+}
+
+// Creates a new branch or root instance of the specified service.
+// Factory parameters resolve their service from the scope when one is provided.
+func (c *RoidsContainer) createTransientBranchDep(service *Service, deps map[string]*any, scope *Scope) (*any, error) {
+	args := func(params []*dependency) ([]reflect.Value, error) {
+		return c.getTransientArgs(params, deps, scope)
+	}
+	argValues, err := args(service.params)
+	if err != nil {
+		return nil, err
+	}
+	instance, err := callInjector(service.Injector, argValues, service.SpecType)
+	if err != nil {
+		return nil, err
+	}
+	return c.decorate(service, instance, args)
+}
+
+// Gets the arguments of the parameters from the already resolved dependencies.
+func (c *RoidsContainer) getTransientArgs(params []*dependency, deps map[string]*any, scope *Scope) ([]reflect.Value, error) {
+	return getArgs(params, func(param *dependency) (reflect.Value, error) {
+		dep, ok := deps[param.service.Id]
+		switch {
+		case param.factory != nil:
+			return c.newFactory(param, scope), nil
+		case ok:
+			return param.value(dep), nil
+		case param.optional:
+			return param.empty(), nil
+		}
+		return reflect.Value{}, core.NewServiceNotRegisteredError(param.service.SpecType)
+	})
+}
+
+// Calls the injector of the service specification with the provided arguments.
+// Injectors returning an error as their second value fail construction when the error is not nil.
+// Instances of concrete specifications are converted to the specification, e.g. a `func() time.Time` to a named `Clock`.
+func callInjector(injector any, args []reflect.Value, specType reflect.Type) (*any, error) {
+	injectorVal := reflect.ValueOf(injector)
+	setConstructing(args, true)
+	results := injectorVal.Call(args)
+	setConstructing(args, false)
+	if len(results) > 1 && !results[1].IsNil() {
+		return nil, core.NewConstructionError(results[1].Interface().(error), specType)
+	}
+	result := results[0]
+	if specType.Kind() != reflect.Interface {
+		result = result.Convert(specType)
+	}
+	dep := result.Interface()
+	return &dep, nil
 }
 
 // Sets a static instance of a branch or root dependency.
 // Static services can depend on Transient services,
 // so we may need to create build one
-func setStaticBranchDep(service *Service) {
-	injector := service.Injector
-	injectorVal := reflect.ValueOf(injector)
-	args := getArgsForFunction(service)
-	results := injectorVal.Call(args)
-	newStaticService := results[0].Interface()
-	service.instance = &newStaticService
+func (c *RoidsContainer) setStaticBranchDep(service *Service) error {
+	args, err := c.getArgsForFunction(service.params)
+	if err != nil {
+		return err
+	}
+	instance, err := callInjector(service.Injector, args, service.SpecType)
+	if err != nil {
+		return err
+	}
+	instance, err = c.decorate(service, instance, c.getArgsForFunction)
+	if err != nil {
+		return err
+	}
+	service.instance = instance
 	service.created = true
-	return
+	return nil
 }
 
-// roidsContainer is a struct that holds all the dependencies for the application.
-// It is recommended to use the `GetRoids` function to get the global instance.
-type roidsContainer struct {
+// RoidsContainer is a struct that holds all the dependencies for the application.
+// Use the `GetRoids` function to get the global instance, or `NewContainer` for an isolated one.
+type RoidsContainer struct {
 	servicesGraph *serviceGraph
 	Logger        *slog.Logger
+	// Timeout of each start and stop hook. No timeout when zero.
+	hookTimeout time.Duration
+	// Static services that have been started, in start order.
+	started []*Service
+	// True if every static service is only constructed on first use.
+	lazy bool
+	// Number of static services constructed concurrently by `Build`. Sequential when 1 or less.
+	workers int
+	// Errors returned when adding services, reported again by `Validate`.
+	registrationErrs []registrationError
+	// Policy for static services capturing dependencies, by lifetime of the dependency.
+	captivePolicies map[string]CaptivePolicy
 }
 
 // Creates a new instance of the dependency container.
-// This function should not be used directly. Use `GetRoids` instead.
-func newRoidsContainer(graph *serviceGraph) *roidsContainer {
-	logFile, _ := os.Create("roids.log")
-	libLogger := slog.New(slog.NewTextHandler(logFile, &slog.HandlerOptions{Level: slog.LevelDebug}))
+// This function should not be used directly. Use `GetRoids` or `NewContainer` instead.
+func newRoidsContainer(graph *serviceGraph, opts ...ContainerOption) *RoidsContainer {
 	if graph == nil {
 		dag2 := core.NewGraph()
-		return &roidsContainer{
-			servicesGraph: newServiceGraph(dag2),
-			Logger:        libLogger,
-		}
-	} else {
-		return &roidsContainer{
-			servicesGraph: graph,
-			Logger:        libLogger,
-		}
+		graph = newServiceGraph(dag2)
+	}
+	container := &RoidsContainer{
+		servicesGraph:   graph,
+		captivePolicies: make(map[string]CaptivePolicy),
+	}
+	for _, opt := range opts {
+		opt(container)
+	}
+	if container.Logger == nil {
+		container.Logger = getDefaultLogger()
 	}
+	return container
 }
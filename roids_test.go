@@ -1,8 +1,12 @@
 package roids_test
 
 import (
+	"errors"
 	"log"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/ShounakA/roids"
 	"github.com/ShounakA/roids/core"
@@ -152,6 +156,16 @@ func (c *MyCache) Delete(k string) {
 	return
 }
 
+var errConnectionRefused = errors.New("connection refused")
+
+func newFailingSqliteProvider() (*SqliteProvider, error) {
+	return nil, errConnectionRefused
+}
+
+func newCheckedSqliteProvider() (*SqliteProvider, error) {
+	return NewSqliteProvider(), nil
+}
+
 func newTestObject(service dependedService) *testObject {
 	return &testObject{
 		something:      "Testing add",
@@ -588,3 +602,563 @@ func TestStaticDependantOnTransient(t *testing.T) {
 	test.DoSomethingBob()
 	roids.UNSAFE_Clear()
 }
+
+func TestNewContainer_Isolated(t *testing.T) {
+	first := roids.NewContainer()
+	second := roids.NewContainer()
+	if first == second {
+		t.Error("Each call should create a new container.")
+	}
+
+	if err := first.AddStaticService(new(myInterface), newShape); err != nil {
+		t.Error("Should be able to add simple dependencies.", err.Error())
+	}
+	if err := first.AddStaticService(new(testInterface), newTestObject); err != nil {
+		t.Error("Should be able to add simple dependencies.", err.Error())
+	}
+	if err := first.AddStaticService(new(dependedService), newDependedObject); err != nil {
+		t.Error("Should be able to add simple dependencies.", err.Error())
+	}
+	if err := second.AddStaticService(new(myInterface), newShapePart2); err != nil {
+		t.Error("Should be able to add simple dependencies.", err.Error())
+	}
+	if err := second.AddTransientService(new(testInterface), newTestObject); err != nil {
+		t.Error("Should be able to add simple dependencies.", err.Error())
+	}
+	if err := second.AddTransientService(new(dependedService), newDependedObject); err != nil {
+		t.Error("Should be able to add simple dependencies.", err.Error())
+	}
+
+	if err := first.Build(); err != nil {
+		t.Error("Should build first container.", err.Error())
+	}
+	if err := second.Build(); err != nil {
+		t.Error("Should build second container.", err.Error())
+	}
+
+	if s := roids.InjectFrom[myInterface](first).SameShape(); s != "testTesting add" {
+		t.Errorf("Expected 'testTesting add' but got %s", s)
+	}
+	if s := roids.InjectFrom[myInterface](second).SameShape(); s != "testsetTesting add" {
+		t.Errorf("Expected 'testsetTesting add' but got %s", s)
+	}
+	if roids.InjectFrom[testInterface](first) == roids.InjectFrom[testInterface](second) {
+		t.Error("Containers should not share instances.")
+	}
+}
+
+func TestNewContainer_ConfigurationBuilder(t *testing.T) {
+	c := roids.NewContainer()
+
+	err := roids.AddConfigurationBuilderTo[TestConfig](c, "./roids.settings.json", core.JsonConfig)
+	if err != nil {
+		t.Error("Should add configuration with no errors")
+	}
+	c.Build()
+
+	cfg := roids.InjectFrom[config.IConfiguration[TestConfig]](c)
+	if msg := cfg.Config().Message; msg != "Test from JSON" {
+		t.Errorf("Should add configuration file. Got %s", msg)
+	}
+}
+
+func TestAddStaticService_InvalidInjectorSignature(t *testing.T) {
+	c := roids.NewContainer()
+
+	err := c.AddStaticService(new(IDbProvider), func() (*SqliteProvider, string) { return nil, "" })
+	if _, ok := err.(*core.InjectorError); !ok {
+		t.Errorf("Second return value must be an error, got %v", err)
+	}
+	err = c.AddStaticService(new(IDbProvider), func() {})
+	if _, ok := err.(*core.InjectorError); !ok {
+		t.Errorf("Injector must return a service, got %v", err)
+	}
+}
+
+func TestBuild_InjectorReturnsError(t *testing.T) {
+	c := roids.NewContainer()
+
+	if err := c.AddStaticService(new(IDbProvider), newFailingSqliteProvider); err != nil {
+		t.Error("Should be able to add injectors returning errors.", err.Error())
+	}
+	if err := c.AddStaticService(new(ICache), NewCache); err != nil {
+		t.Error("Should be able to add simple dependencies.", err.Error())
+	}
+	if err := c.AddStaticService(new(ITodoRepository), NewTodoRepository); err != nil {
+		t.Error("Should be able to add simple dependencies.", err.Error())
+	}
+
+	err := c.Build()
+	var constructionErr *core.ConstructionError
+	if !errors.As(err, &constructionErr) {
+		t.Fatalf("Build should report a ConstructionError, got %v", err)
+	}
+	if constructionErr.SpecType.Name() != "IDbProvider" {
+		t.Errorf("ConstructionError should name the failing service, got %s", constructionErr.SpecType)
+	}
+	if !errors.Is(err, errConnectionRefused) {
+		t.Error("ConstructionError should wrap the injector error.")
+	}
+}
+
+func TestInjectFromE_TransientInjectorReturnsError(t *testing.T) {
+	c := roids.NewContainer()
+
+	_ = c.AddTransientService(new(IDbProvider), newFailingSqliteProvider)
+	_ = c.AddStaticService(new(ICache), NewCache)
+	_ = c.AddTransientService(new(ITodoRepository), NewTodoRepository)
+	if err := c.Build(); err != nil {
+		t.Error("Transient services should not be built.", err.Error())
+	}
+
+	_, err := roids.InjectFromE[ITodoRepository](c)
+	if !errors.Is(err, errConnectionRefused) {
+		t.Errorf("Should surface the transient construction error, got %v", err)
+	}
+}
+
+func TestInjectFromE_InjectorReturnsNoError(t *testing.T) {
+	c := roids.NewContainer()
+
+	_ = c.AddStaticService(new(IDbProvider), newCheckedSqliteProvider)
+	if err := c.Build(); err != nil {
+		t.Error("Should build the container.", err.Error())
+	}
+
+	db, err := roids.InjectFromE[IDbProvider](c)
+	if err != nil || db == nil {
+		t.Errorf("Should inject the service, got %v", err)
+	}
+}
+
+func TestInjectFromE_InjectorReturnsNilInterface(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddStaticService(new(IDbProvider), func() IDbProvider { return nil })
+	_ = c.AddTransientService(new(ICache), func() ICache { return nil })
+	_ = c.AddTransientService(new(ITodoRepository), NewTodoRepository)
+	if err := c.Build(); err != nil {
+		t.Fatal("Should build the container.", err.Error())
+	}
+
+	if db, err := roids.InjectFromE[IDbProvider](c); err != nil || db != nil {
+		t.Errorf("Should inject the nil static service, got %v, %v", db, err)
+	}
+	if cache, ok := roids.TryInjectFrom[ICache](c); !ok || cache != nil {
+		t.Errorf("Should inject the nil transient service, got %v", cache)
+	}
+	repo, err := roids.InjectFromE[ITodoRepository](c)
+	if err != nil || repo.(*TodoRepository).db != nil {
+		t.Errorf("Should inject nil dependencies, got %v", err)
+	}
+	if _, err := c.Invoke(func(db roids.Optional[IDbProvider], cache roids.Provider[ICache]) error {
+		if value, ok := db.Get(); !ok || value != nil {
+			return errors.New("should wrap the nil dependency")
+		}
+		_, err := cache.GetE()
+		return err
+	}); err != nil {
+		t.Error("Should resolve nil dependencies on demand.", err.Error())
+	}
+}
+
+func TestInjectorReturnsNilInterface_Groups(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddStaticService(new(ICache), func() ICache { return nil }, roids.InGroup())
+	_ = c.AddTransientService(new(ICache), NewCache, roids.InGroup())
+	if err := c.Build(); err != nil {
+		t.Fatal("Should build the container.", err.Error())
+	}
+
+	caches, err := roids.InjectFromE[[]ICache](c)
+	if err != nil || len(caches) != 2 || caches[0] != nil || caches[1] == nil {
+		t.Errorf("Should collect nil group members, got %v, %v", caches, err)
+	}
+}
+
+func TestInjectorReturnsNilInterface_Decorators(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddStaticService(new(IDbProvider), func() IDbProvider { return nil })
+	_ = roids.DecorateTo[IDbProvider](c, func(inner IDbProvider) IDbProvider {
+		if inner == nil {
+			return NewSqliteProvider()
+		}
+		return inner
+	})
+	if err := c.Build(); err != nil {
+		t.Fatal("Should build the container.", err.Error())
+	}
+	if db := roids.InjectFrom[IDbProvider](c); db == nil {
+		t.Error("Should decorate nil services.")
+	}
+}
+
+func TestInjectorReturnsNilInterface_Aliases(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.Provide(func() IDbProvider { return nil }, roids.As[any]())
+	if err := c.Build(); err != nil {
+		t.Fatal("Should build the container.", err.Error())
+	}
+	if alias, err := roids.InjectFromE[any](c); err != nil || alias != nil {
+		t.Errorf("Aliases of nil services should be nil, got %v, %v", alias, err)
+	}
+}
+
+func TestInjectE_ServiceNotRegistered(t *testing.T) {
+	c := roids.NewContainer()
+
+	_, err := roids.InjectFromE[ICache](c)
+	if _, ok := err.(*core.ServiceNotRegisteredError); !ok {
+		t.Errorf("Expected ServiceNotRegisteredError, got %v", err)
+	}
+
+	// Registered only as a dependency of another service.
+	_ = c.AddTransientService(new(ITodoRepository), NewTodoRepository)
+	_, err = roids.InjectFromE[IDbProvider](c)
+	if _, ok := err.(*core.ServiceNotRegisteredError); !ok {
+		t.Errorf("Expected ServiceNotRegisteredError, got %v", err)
+	}
+	_, err = roids.InjectFromE[ITodoRepository](c)
+	if _, ok := err.(*core.ServiceNotRegisteredError); !ok {
+		t.Errorf("Expected ServiceNotRegisteredError for missing dependency, got %v", err)
+	}
+}
+
+func TestInjectE_ContainerNotBuilt(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddStaticService(new(ICache), NewCache)
+
+	_, err := roids.InjectFromE[ICache](c)
+	if _, ok := err.(*core.ContainerNotBuiltError); !ok {
+		t.Errorf("Expected ContainerNotBuiltError, got %v", err)
+	}
+
+	_ = c.Build()
+	if _, err = roids.InjectFromE[ICache](c); err != nil {
+		t.Error("Should inject after building.", err.Error())
+	}
+}
+
+func TestInject_PanicsWithTypedError(t *testing.T) {
+	c := roids.NewContainer()
+
+	defer func() {
+		err, _ := recover().(error)
+		if _, ok := err.(*core.ServiceNotRegisteredError); !ok {
+			t.Errorf("Expected ServiceNotRegisteredError, got %v", err)
+		}
+	}()
+	_ = roids.InjectFrom[ICache](c)
+}
+
+func TestTryInject(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddStaticService(new(ICache), NewCache)
+
+	if _, ok := roids.TryInjectFrom[ICache](c); ok {
+		t.Error("Should not inject before building.")
+	}
+	if _, ok := roids.TryInjectFrom[IDbProvider](c); ok {
+		t.Error("Should not inject unregistered services.")
+	}
+	_ = c.Build()
+	if cache, ok := roids.TryInjectFrom[ICache](c); !ok || cache == nil {
+		t.Error("Should inject registered services.")
+	}
+}
+
+func TestBuild_DependencyNotRegistered(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddStaticService(new(ICache), NewCache)
+	_ = c.AddStaticService(new(ITodoRepository), NewTodoRepository)
+
+	err := c.Build()
+	if _, ok := err.(*core.ServiceNotRegisteredError); !ok {
+		t.Errorf("Expected ServiceNotRegisteredError, got %v", err)
+	}
+}
+
+func TestWithLazy(t *testing.T) {
+	c := roids.NewContainer()
+	var constructed atomic.Int32
+	newCountedCache := func() *MyCache {
+		constructed.Add(1)
+		return NewCache()
+	}
+
+	_ = c.AddStaticService(new(ICache), newCountedCache, roids.WithLazy())
+	if err := c.Build(); err != nil {
+		t.Error("Should build the container.", err.Error())
+	}
+	if constructed.Load() != 0 {
+		t.Error("Lazy services should not be constructed by Build.")
+	}
+
+	var wg sync.WaitGroup
+	caches := make([]ICache, 10)
+	for i := range caches {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			caches[i] = roids.InjectFrom[ICache](c)
+		}(i)
+	}
+	wg.Wait()
+	if constructed.Load() != 1 {
+		t.Errorf("Lazy services should be constructed once, got %d", constructed.Load())
+	}
+	for _, cache := range caches {
+		if cache != caches[0] {
+			t.Error("Lazy static services should be shared.")
+		}
+	}
+}
+
+func TestWithLazy_ConstructedByDependant(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddStaticService(new(IDbProvider), NewSqliteProvider, roids.WithLazy())
+	_ = c.AddStaticService(new(ICache), NewCache, roids.WithLazy())
+	_ = c.AddStaticService(new(ITodoRepository), NewTodoRepository)
+	if err := c.Build(); err != nil {
+		t.Error("Should build the container.", err.Error())
+	}
+
+	repo := roids.InjectFrom[ITodoRepository](c).(*TodoRepository)
+	if repo.db != roids.InjectFrom[IDbProvider](c) {
+		t.Error("Lazy dependencies should be constructed for their dependants and shared.")
+	}
+}
+
+func TestWithLazy_InjectedWhileBuilding(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddStaticService(new(ICache), NewCache, roids.WithLazy())
+	_ = c.AddStaticService(new(IDbProvider), NewSqliteProvider)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_ = c.Build()
+		}()
+		go func() {
+			defer wg.Done()
+			_ = roids.InjectFrom[ICache](c)
+		}()
+	}
+	wg.Wait()
+	if roids.InjectFrom[ICache](c) == nil {
+		t.Error("Lazy services should be injected while the container is building.")
+	}
+}
+
+func TestWithLazyStatics_ReportsErrorOnInject(t *testing.T) {
+	c := roids.NewContainer(roids.WithLazyStatics())
+	_ = c.AddStaticService(new(IDbProvider), newFailingSqliteProvider)
+	_ = c.AddStaticService(new(ICache), NewCache)
+	_ = c.AddStaticService(new(ITodoRepository), NewTodoRepository)
+	if err := c.Build(); err != nil {
+		t.Error("Lazy services should not fail the build.", err.Error())
+	}
+
+	if _, err := roids.InjectFromE[ICache](c); err != nil {
+		t.Error("Should construct lazy services without failing dependencies.", err.Error())
+	}
+	for i := 0; i < 2; i++ {
+		_, err := roids.InjectFromE[ITodoRepository](c)
+		if !errors.Is(err, errConnectionRefused) {
+			t.Errorf("Should report the construction error of lazy dependencies, got %v", err)
+		}
+	}
+}
+
+func TestWithParallelBuild(t *testing.T) {
+	const workers = 3
+	c := roids.NewContainer(roids.WithParallelBuild(workers))
+	var running, maxRunning atomic.Int32
+	newSlowProvider := func() *SqliteProvider {
+		n := running.Add(1)
+		for {
+			peak := maxRunning.Load()
+			if n <= peak || maxRunning.CompareAndSwap(peak, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		running.Add(-1)
+		return NewSqliteProvider()
+	}
+
+	keys := []string{"a", "b", "c", "d", "e", "f"}
+	for _, key := range keys {
+		if err := c.AddKeyedStaticService(key, new(IDbProvider), newSlowProvider); err != nil {
+			t.Error("Should be able to add keyed dependencies.", err.Error())
+		}
+	}
+	_ = c.AddStaticService(new(ICache), NewCache)
+	_ = c.AddStaticService(new(ITodoRepository), NewTodoRepository, roids.WithParamKey(0, "a"))
+	if err := c.Build(); err != nil {
+		t.Error("Should build the container.", err.Error())
+	}
+
+	if maxRunning.Load() > workers {
+		t.Errorf("Should construct at most %d services at once, got %d", workers, maxRunning.Load())
+	}
+	for _, key := range keys {
+		if _, err := roids.InjectKeyedFromE[IDbProvider](c, key); err != nil {
+			t.Error("Should build every static service.", err.Error())
+		}
+	}
+	repo := roids.InjectFrom[ITodoRepository](c).(*TodoRepository)
+	if repo.db != roids.InjectKeyedFrom[IDbProvider](c, "a") {
+		t.Error("Dependants should be built from the static instances of their dependencies.")
+	}
+}
+
+func TestWithParallelBuild_InjectorReturnsError(t *testing.T) {
+	c := roids.NewContainer(roids.WithParallelBuild(4))
+	_ = c.AddStaticService(new(IDbProvider), newFailingSqliteProvider)
+	_ = c.AddStaticService(new(ICache), NewCache)
+	_ = c.AddStaticService(new(ITodoRepository), NewTodoRepository)
+
+	err := c.Build()
+	var constructionErr *core.ConstructionError
+	if !errors.As(err, &constructionErr) || !errors.Is(err, errConnectionRefused) {
+		t.Errorf("Build should report a ConstructionError, got %v", err)
+	}
+	if _, err := roids.InjectFromE[ITodoRepository](c); err == nil {
+		t.Error("Dependants of a failing service should not be built.")
+	}
+}
+
+func TestWithParallelBuild_SameErrorAsSequential(t *testing.T) {
+	addFailingServices := func(c *roids.RoidsContainer) {
+		_ = c.AddStaticService(new(ICache), NewCache)
+		_ = c.AddStaticService(new(IDbProvider), NewSqliteProvider)
+		_ = c.AddStaticService(new(myInterface), func(cache ICache) (*shape, error) { return nil, errors.New("shape unavailable") })
+		_ = c.AddStaticService(new(ITodoRepository), func(db IDbProvider) (*TodoRepository, error) { return nil, errConnectionRefused })
+	}
+	sequential := roids.NewContainer()
+	addFailingServices(sequential)
+	expected := sequential.Build()
+	if expected == nil {
+		t.Fatal("Build should fail.")
+	}
+
+	for i := 0; i < 20; i++ {
+		c := roids.NewContainer(roids.WithParallelBuild(4))
+		addFailingServices(c)
+		if err := c.Build(); err == nil || err.Error() != expected.Error() {
+			t.Fatalf("Expected the error of the sequential build %v, got %v", expected, err)
+		}
+	}
+}
+
+type (
+	clock func() time.Time
+
+	appConfig struct {
+		Name string
+	}
+
+	clockedService struct {
+		now    clock
+		config *appConfig
+	}
+)
+
+// Adds a function-local type named settings, and gets the name it is injected with.
+func addLocalSettings(c *roids.RoidsContainer, name string) (func() string, error) {
+	type settings struct{ name string }
+	err := c.AddStaticService(new(settings), func() settings { return settings{name: name} })
+	return func() string { return roids.InjectFrom[settings](c).name }, err
+}
+
+// Adds another function-local type named settings.
+func addOtherLocalSettings(c *roids.RoidsContainer, name string) (func() string, error) {
+	type settings struct{ name string }
+	err := c.AddStaticService(new(settings), func() settings { return settings{name: name} })
+	return func() string { return roids.InjectFrom[settings](c).name }, err
+}
+
+func TestAddStaticService_LocalTypes(t *testing.T) {
+	c := roids.NewContainer()
+	first, err := addLocalSettings(c, "first")
+	if err != nil {
+		t.Error("Should add the first local type.", err.Error())
+	}
+	second, err := addOtherLocalSettings(c, "second")
+	if err != nil {
+		t.Error("Should add another local type of the same name.", err.Error())
+	}
+	_ = c.Build()
+	if first() != "first" || second() != "second" {
+		t.Errorf("Local types of the same name should be distinct services, got %s and %s", first(), second())
+	}
+}
+
+func TestAddStaticService_MissingConcreteSlice(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddStaticService(new(*appConfig), func(data []byte) *appConfig { return &appConfig{Name: string(data)} })
+
+	var missing *core.ServiceNotRegisteredError
+	if err := c.Validate(); !errors.As(err, &missing) || missing.SpecType.String() != "[]uint8" {
+		t.Errorf("Validate should report the missing slice, got %v", err)
+	}
+	if _, ok := c.Build().(*core.ServiceNotRegisteredError); !ok {
+		t.Error("Build should not inject an empty slice for a missing concrete specification.")
+	}
+}
+
+func TestAddStaticService_ConcreteSpecifications(t *testing.T) {
+	c := roids.NewContainer()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if err := c.AddStaticService(new(clock), func() func() time.Time { return func() time.Time { return now } }); err != nil {
+		t.Error("Should add func specifications.", err.Error())
+	}
+	if err := c.AddStaticService(new(*appConfig), func() *appConfig { return &appConfig{Name: "roids"} }); err != nil {
+		t.Error("Should add pointer specifications.", err.Error())
+	}
+	if err := c.AddStaticService(new(appConfig), func(config *appConfig) appConfig { return *config }); err != nil {
+		t.Error("Should add struct specifications.", err.Error())
+	}
+	if err := c.AddTransientService(new(*clockedService), func(now clock, config *appConfig) *clockedService {
+		return &clockedService{now: now, config: config}
+	}); err != nil {
+		t.Error("Should depend on concrete specifications.", err.Error())
+	}
+	_ = c.AddStaticService(new([]byte), func() []byte { return []byte("roids") })
+	_ = c.AddStaticService(new(map[string]int), func() map[string]int { return map[string]int{"roids": 1} })
+	if err := c.Build(); err != nil {
+		t.Error("Should build concrete specifications.", err.Error())
+	}
+
+	service := roids.InjectFrom[*clockedService](c)
+	if service.now() != now || service.config != roids.InjectFrom[*appConfig](c) {
+		t.Error("Should inject concrete dependencies.")
+	}
+	if roids.InjectFrom[appConfig](c).Name != "roids" {
+		t.Error("Should inject struct values.")
+	}
+	if string(roids.InjectFrom[[]byte](c)) != "roids" || roids.InjectFrom[map[string]int](c)["roids"] != 1 {
+		t.Error("Unnamed specifications should not collide.")
+	}
+}
+
+func TestAddStaticService_NotAssignable(t *testing.T) {
+	c := roids.NewContainer()
+
+	err := c.AddStaticService(new(*appConfig), NewCache)
+	serviceErr, ok := err.(*core.ServiceError)
+	if !ok {
+		t.Fatalf("Expected ServiceError, got %v", err)
+	}
+	if serviceErr.Error() != "[*roids_test.appConfig] '*roids_test.MyCache' must be assignable to '*roids_test.appConfig' to be added as a service." {
+		t.Errorf("Unexpected error message: %s", serviceErr.Error())
+	}
+
+	err = c.AddStaticService(new(ICache), NewSqliteProvider)
+	if err.Error() != "[roids_test.ICache] '*roids_test.SqliteProvider' must implement 'roids_test.ICache' to be added as a service." {
+		t.Errorf("Unexpected error message: %s", err.Error())
+	}
+}
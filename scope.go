@@ -0,0 +1,143 @@
+package roids
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ShounakA/roids/core"
+)
+
+// Resolver is anything services can be injected from. Implemented by `*RoidsContainer` and `*Scope`.
+type Resolver interface {
+	container() *RoidsContainer
+	scope() *Scope
+}
+
+// Scope caches one instance of every scoped service for its lifetime.
+// Create one per unit of work (e.g. a request) and close it when the work is done.
+type Scope struct {
+	roids *RoidsContainer
+	// Scoped instances by service ID.
+	instances map[string]*scopedInstance
+	// Scoped services in the order they were created.
+	created []*Service
+	closed  bool
+	mu      sync.Mutex
+}
+
+// Instance of a scoped service, constructed once per scope even when resolved concurrently.
+type scopedInstance struct {
+	once     sync.Once
+	instance *any
+	err      error
+}
+
+// Creates a new scope from the global container.
+func CreateScope() *Scope {
+	return GetRoids().CreateScope()
+}
+
+// Creates a new scope from the container.
+func (c *RoidsContainer) CreateScope() *Scope {
+	return &Scope{
+		roids:     c,
+		instances: make(map[string]*scopedInstance),
+	}
+}
+
+// Closes the scope, disposing every scoped instance implementing `Disposer` or `io.Closer` in reverse creation order.
+func (s *Scope) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	var errs []error
+	disposed := instanceSet{}
+	for i := len(s.created) - 1; i >= 0; i-- {
+		service := s.created[i]
+		instance := *s.instances[service.Id].instance
+		if !disposed.add(instance) {
+			continue
+		}
+		s.roids.Logger.Debug(fmt.Sprintf("Disposing scoped service %s:%s", service.ID(), service.SpecType.String()))
+		if err := dispose(context.Background(), instance); err != nil {
+			errs = append(errs, core.NewDisposeError(err, service.SpecType))
+		}
+	}
+	s.instances = nil
+	s.created = nil
+	return errors.Join(errs...)
+}
+
+func (s *Scope) container() *RoidsContainer {
+	return s.roids
+}
+
+func (s *Scope) scope() *Scope {
+	return s
+}
+
+func (c *RoidsContainer) container() *RoidsContainer {
+	return c
+}
+
+func (c *RoidsContainer) scope() *Scope {
+	return nil
+}
+
+// True if the scoped instance of the service was created, or is being created, in the scope.
+func (s *Scope) has(service *Service) bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.instances[service.Id]
+	return ok
+}
+
+// Gets the scoped instance of the service, creating it from the already resolved dependencies if needed.
+// The scope is not locked while the instance is constructed, so its injector can resolve other scoped services
+// from the scope, e.g. through a `Provider`.
+func (s *Scope) getOrCreate(service *Service, deps map[string]*any) (*any, error) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil, core.NewScopeClosedError(service.SpecType)
+	}
+	scoped, ok := s.instances[service.Id]
+	if !ok {
+		scoped = &scopedInstance{}
+		s.instances[service.Id] = scoped
+	}
+	s.mu.Unlock()
+
+	scoped.once.Do(func() {
+		// Root services can still resolve factory and deferred parameters from the scope.
+		scoped.instance, scoped.err = s.roids.createTransientBranchDep(service, deps, s)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		switch {
+		case scoped.err != nil:
+			// Failed constructions are retried on the next resolution.
+			if s.instances[service.Id] == scoped {
+				delete(s.instances, service.Id)
+			}
+		case s.closed:
+			// The scope was closed during construction, so the instance is not disposed with it.
+			if service.aliasOf == nil {
+				_ = dispose(context.Background(), *scoped.instance)
+			}
+			scoped.instance, scoped.err = nil, core.NewScopeClosedError(service.SpecType)
+		case service.aliasOf == nil:
+			// Aliases are disposed with the service they were added with.
+			s.created = append(s.created, service)
+		}
+	})
+	return scoped.instance, scoped.err
+}
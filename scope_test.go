@@ -0,0 +1,175 @@
+package roids_test
+
+import (
+	"testing"
+
+	"github.com/ShounakA/roids"
+	"github.com/ShounakA/roids/core"
+)
+
+type (
+	iRequestLogger interface {
+		Log(msg string)
+	}
+
+	iUnitOfWork interface {
+		Logger() iRequestLogger
+	}
+
+	requestLogger struct {
+		cache  ICache
+		lines  []string
+		closed bool
+	}
+
+	unitOfWork struct {
+		logger iRequestLogger
+	}
+
+	staticReporter struct {
+		logger iRequestLogger
+	}
+)
+
+func newRequestLogger(cache ICache) *requestLogger {
+	return &requestLogger{cache: cache}
+}
+
+func (r *requestLogger) Log(msg string) {
+	r.lines = append(r.lines, msg)
+}
+
+func (r *requestLogger) Close() error {
+	r.closed = true
+	return nil
+}
+
+func newUnitOfWork(logger iRequestLogger) *unitOfWork {
+	return &unitOfWork{logger: logger}
+}
+
+func (u *unitOfWork) Logger() iRequestLogger {
+	return u.logger
+}
+
+func newStaticReporter(logger iRequestLogger) *staticReporter {
+	return &staticReporter{logger: logger}
+}
+
+func (s *staticReporter) SameShape() string {
+	return "reporter"
+}
+
+func newScopedContainer(t *testing.T) *roids.RoidsContainer {
+	c := roids.NewContainer()
+	if err := c.AddStaticService(new(ICache), NewCache); err != nil {
+		t.Error("Should be able to add simple dependencies.", err.Error())
+	}
+	if err := c.AddScopedService(new(iRequestLogger), newRequestLogger); err != nil {
+		t.Error("Should be able to add scoped dependencies.", err.Error())
+	}
+	if err := c.AddTransientService(new(iUnitOfWork), newUnitOfWork); err != nil {
+		t.Error("Should be able to add simple dependencies.", err.Error())
+	}
+	if err := c.Build(); err != nil {
+		t.Error("Should build the container.", err.Error())
+	}
+	return c
+}
+
+func TestScope_SharedWithinScope(t *testing.T) {
+	c := newScopedContainer(t)
+
+	scope := c.CreateScope()
+	first := roids.InjectFrom[iRequestLogger](scope)
+	second := roids.InjectFrom[iRequestLogger](scope)
+	if first != second {
+		t.Error("Scoped services should be the same within a scope.")
+	}
+
+	uow := roids.InjectFrom[iUnitOfWork](scope)
+	if uow.Logger() != first {
+		t.Error("Transient services should receive the scoped instance of the scope.")
+	}
+
+	otherScope := c.CreateScope()
+	other := roids.InjectFrom[iRequestLogger](otherScope)
+	if other == first {
+		t.Error("Scoped services should not be shared across scopes.")
+	}
+	if other.(*requestLogger).cache != first.(*requestLogger).cache {
+		t.Error("Scoped services should share static dependencies.")
+	}
+}
+
+func TestScope_DependenciesBuiltOncePerScope(t *testing.T) {
+	c := roids.NewContainer()
+	calls := 0
+	_ = c.AddTransientService(new(ICache), func() *MyCache {
+		calls++
+		return NewCache()
+	})
+	_ = c.AddScopedService(new(iRequestLogger), newRequestLogger)
+	_ = c.Build()
+
+	scope := c.CreateScope()
+	defer scope.Close()
+	for i := 0; i < 3; i++ {
+		_ = roids.InjectFrom[iRequestLogger](scope)
+	}
+	if calls != 1 {
+		t.Errorf("Dependencies of a scoped service should be built once per scope, built %d times.", calls)
+	}
+
+	other := c.CreateScope()
+	defer other.Close()
+	_ = roids.InjectFrom[iRequestLogger](other)
+	if calls != 2 {
+		t.Errorf("Dependencies should be built again for a new scope, built %d times.", calls)
+	}
+}
+
+func TestScope_Close(t *testing.T) {
+	c := newScopedContainer(t)
+
+	scope := c.CreateScope()
+	logger := roids.InjectFrom[iRequestLogger](scope).(*requestLogger)
+	if err := scope.Close(); err != nil {
+		t.Error("Should close the scope.", err.Error())
+	}
+	if !logger.closed {
+		t.Error("Closing the scope should dispose scoped services.")
+	}
+
+	defer func() {
+		err, _ := recover().(error)
+		if _, ok := err.(*core.ScopeClosedError); !ok {
+			t.Errorf("Expected ScopeClosedError, got %v", err)
+		}
+	}()
+	_ = roids.InjectFrom[iRequestLogger](scope)
+}
+
+func TestScope_InjectFromContainer(t *testing.T) {
+	c := newScopedContainer(t)
+
+	defer func() {
+		err, _ := recover().(error)
+		if _, ok := err.(*core.ScopedServiceError); !ok {
+			t.Errorf("Expected ScopedServiceError, got %v", err)
+		}
+	}()
+	_ = roids.InjectFrom[iRequestLogger](c)
+}
+
+func TestScope_StaticDependantOnScoped(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddStaticService(new(ICache), NewCache)
+	_ = c.AddScopedService(new(iRequestLogger), newRequestLogger)
+	_ = c.AddStaticService(new(myInterface), newStaticReporter)
+
+	err := c.Build()
+	if _, ok := err.(*core.ScopedServiceError); !ok {
+		t.Errorf("Static services should not be built from scoped services, got %v", err)
+	}
+}
@@ -4,7 +4,8 @@
  */
 
 // Package containing custom dependency container for dependency injection.
-// There is only ever one container and it can be used globally to access all the dependencies.
+// A global container can be used to access all the dependencies, and independent
+// containers can be created with `NewContainer`.
 package roids
 
 import (
@@ -12,10 +13,11 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"strings"
+	"sync"
 
 	"github.com/ShounakA/roids/core"
 	"github.com/ShounakA/roids/core/config"
-	"github.com/google/uuid"
 	"gopkg.in/yaml.v3"
 )
 
@@ -27,129 +29,610 @@ type Service struct {
 	Id string
 	// The service specification or interface type
 	SpecType reflect.Type
-	// The lifetime of the service. Can either be "static" or "transient"
+	// Key distinguishing registrations of the same specification. Empty for the default registration.
+	Key string
+	// The lifetime of the service. Can either be "static", "transient" or "scoped"
 	lifetimeType string
 	// True if the service has already been created once. False otherwise.
 	created bool
+	// True if the static service is only constructed on first use.
+	lazy bool
+	// True if the static instance was added with `AddInstance`, rather than constructed by an injector.
+	provided bool
+	// The service resolving to the same instance, if the service was added with `As`.
+	aliasOf *Service
+	// Ensures the static instance is only constructed once until it is disposed.
+	guard staticGuard
 	// The service concrete implementation type
 	implType reflect.Type
-	// The instantiated service. nil for service with "transient" lifetime
+	// The instantiated service. nil for service with "transient" or "scoped" lifetime
 	instance *any
-	// True the dependency does not require another to be instantiated.
-	isLeaf bool
+	// The dependency resolved for each parameter of the injector, in order.
+	params []*dependency
+	// True if the service is a slice collecting every member of a group.
+	isGroup bool
+	// Members of the group in registration order. Only used by group services.
+	members []*Service
+	// Decorators wrapping the instance, innermost first.
+	decorators []*serviceDecorator
+	// Hooks run with the static instance when the container is started.
+	onStart []Hook
+	// Hooks run with the static instance when the container is stopped.
+	onStop []Hook
+	// True if the static instance has been started and not stopped since.
+	started bool
+}
+
+// A dependency of a service, resolved for one parameter of its injector.
+type dependency struct {
+	// The service injected into the parameter.
+	service *Service
+	// True if the zero value is injected when the service is not registered.
+	optional bool
+	// Type of the `Provider`, `Lazy` or `Deferred` parameter resolving the service on demand. nil if the service is injected directly.
+	factory reflect.Type
+	// The service constructed with a `Deferred` parameter, to detect resolutions recursing into its construction.
+	dependant *Service
+	// Type of the `Optional` parameter wrapping the service. nil if the service is injected directly.
+	wrapper reflect.Type
+	// Type of the parameter object embedding `In`. nil if the parameter is not a parameter object.
+	object reflect.Type
+	// The dependency resolved for each field of the parameter object, in order.
+	fields []*dependency
+	// Index of the field in the parameter object the dependency is resolved for.
+	index int
+}
+
+// Gets the argument of the parameter, resolving the service of each dependency with resolve.
+// Parameter objects are created with each of their fields resolved.
+func (d *dependency) arg(resolve func(*dependency) (reflect.Value, error)) (reflect.Value, error) {
+	if d.object == nil {
+		return resolve(d)
+	}
+	object := reflect.New(d.object).Elem()
+	for _, field := range d.fields {
+		value, err := field.arg(resolve)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		object.Field(field.index).Set(value)
+	}
+	return object, nil
+}
+
+// Gets the dependencies resolving a service, expanding the fields of parameter objects.
+func (d *dependency) flatten() []*dependency {
+	if d.object == nil {
+		return []*dependency{d}
+	}
+	var deps []*dependency
+	for _, field := range d.fields {
+		deps = append(deps, field.flatten()...)
+	}
+	return deps
+}
+
+// Creates the dependency of an injector parameter of the type.
+// Returns the type of the service it resolves, and the kind of edge to the service.
+func newDependency(paramType reflect.Type, optional bool) (*dependency, reflect.Type, edgeKind) {
+	param := &dependency{optional: optional}
+	depType := paramType
+	kind := dependencyEdge
+	if serviceType, ok := factoryOf(paramType); ok {
+		param.factory = paramType
+		depType = serviceType
+		kind = factoryEdge
+		if paramType.Implements(deferredType) {
+			kind = deferredEdge
+		}
+	} else if serviceType, ok := optionalOf(paramType); ok {
+		param.optional = true
+		param.wrapper = paramType
+		depType = serviceType
+	}
+	if param.optional && kind == dependencyEdge {
+		kind = optionalEdge
+	}
+	return param, depType, kind
+}
+
+// Gets the argument of the parameter holding the instance of its service.
+func (d *dependency) value(instance *any) reflect.Value {
+	if d.wrapper != nil {
+		return reflect.ValueOf(reflect.Zero(d.wrapper).Interface().(optional).newOptional(*instance))
+	}
+	return valueOf(*instance, d.service.SpecType)
+}
+
+// Gets the argument of an optional parameter whose service is not registered.
+func (d *dependency) empty() reflect.Value {
+	if d.wrapper != nil {
+		return reflect.Zero(d.wrapper)
+	}
+	return reflect.Zero(d.service.SpecType)
+}
+
+// Gets the value of the instance of a service of the type.
+// Injectors can return a nil interface, which is injected as the zero value of the type.
+func valueOf(instance any, t reflect.Type) reflect.Value {
+	if instance == nil {
+		return reflect.Zero(t)
+	}
+	return reflect.ValueOf(instance)
+}
+
+// Adds the IDs of the service, and of the dependencies that must be resolved before it is constructed, to required.
+// Static services resolve their own dependencies, and factories resolve theirs on demand.
+// Scoped services already created in the scope, when not nil, are reused without their dependencies.
+func (s *Service) collectRequired(required map[string]bool, scope *Scope) {
+	if required[s.Id] {
+		return
+	}
+	required[s.Id] = true
+	if s.lifetimeType == core.StaticLifetime || (s.lifetimeType == core.ScopedLifetime && scope.has(s)) {
+		return
+	}
+	for _, param := range s.allParams() {
+		if param.factory == nil {
+			param.service.collectRequired(required, scope)
+		}
+	}
+	for _, member := range s.members {
+		member.collectRequired(required, scope)
+	}
+}
+
+// Gets the dependencies of the injector and of every decorator of the service.
+// The fields of parameter objects are included instead of the objects themselves.
+func (s *Service) allParams() []*dependency {
+	var params []*dependency
+	for _, param := range s.params {
+		params = append(params, param.flatten()...)
+	}
+	for _, decorator := range s.decorators {
+		for _, param := range decorator.params {
+			params = append(params, param.flatten()...)
+		}
+	}
+	return params
+}
+
+// True if the service was registered, rather than only added as a placeholder by its dependants.
+func (s *Service) registered() bool {
+	return s.lifetimeType != "" || s.isGroup
+}
+
+// Guards the construction of a static instance. Unlike a `sync.Once`, it is reset when the instance is disposed,
+// so the container can be built again after `Shutdown`.
+type staticGuard struct {
+	mu   sync.Mutex
+	done bool
+	// The error returned when constructing the static instance, if any.
+	err error
+}
 
-	isRoot bool
+// Runs construct, unless it already ran since the guard was reset, and returns its error.
+func (g *staticGuard) do(construct func() error) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.done {
+		g.err = construct()
+		g.done = true
+	}
+	return g.err
+}
+
+// Allows construct to run again.
+func (g *staticGuard) reset() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.done = false
+	g.err = nil
+}
+
+// Forgets the static instance of the service, so it is constructed again when the container is built.
+func (s *Service) resetStatic() {
+	s.instance = nil
+	s.created = false
+	s.guard.reset()
 }
 
 // String function for *Service type.
 func (s *Service) String() string {
+	if s.Key != "" {
+		return fmt.Sprintf("%s:%s[%s]", s.lifetimeType, s.SpecType, s.Key)
+	}
 	return fmt.Sprintf("%s:%s", s.lifetimeType, s.SpecType)
 }
 
 // ID function for *Service type.
+// The ID of the vertex of the service once added to the graph, and its readable label otherwise.
 func (s *Service) ID() string {
-	return uuid.NewSHA1(uuid.UUID{}, []byte(s.SpecType.Name())).String()
+	if s.Id != "" {
+		return s.Id
+	}
+	return s.label()
 }
 
-// Adds a static service to the container. A static service is only created once and lives for the life of the application.
+// Gets the readable label of the service: the package-qualified specification type, followed by the key if there is one.
+// Distinct types can share a label, e.g. function-local types of the same name.
+func (s *Service) label() string {
+	id := typeID(s.SpecType)
+	if s.Key != "" {
+		id = fmt.Sprintf("%s#%s", id, s.Key)
+	}
+	return id
+}
+
+// Gets a readable identity of the type, unique across packages.
+// Named types are qualified by their package path, and unnamed types are built from the identity of their elements.
+func typeID(t reflect.Type) string {
+	if t.Name() != "" {
+		if t.PkgPath() == "" {
+			return t.Name()
+		}
+		// The name of generic types includes the package-qualified type arguments.
+		return t.PkgPath() + "." + t.Name()
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return "*" + typeID(t.Elem())
+	case reflect.Slice:
+		return "[]" + typeID(t.Elem())
+	case reflect.Array:
+		return fmt.Sprintf("[%d]%s", t.Len(), typeID(t.Elem()))
+	case reflect.Map:
+		return fmt.Sprintf("map[%s]%s", typeID(t.Key()), typeID(t.Elem()))
+	case reflect.Chan:
+		switch t.ChanDir() {
+		case reflect.RecvDir:
+			return "<-chan " + typeID(t.Elem())
+		case reflect.SendDir:
+			return "chan<- " + typeID(t.Elem())
+		}
+		return "chan " + typeID(t.Elem())
+	case reflect.Func:
+		in := make([]string, t.NumIn())
+		for i := range in {
+			in[i] = typeID(t.In(i))
+		}
+		if t.IsVariadic() {
+			in[len(in)-1] = "..." + typeID(t.In(len(in)-1).Elem())
+		}
+		out := make([]string, t.NumOut())
+		for i := range out {
+			out[i] = typeID(t.Out(i))
+		}
+		id := fmt.Sprintf("func(%s)", strings.Join(in, ", "))
+		switch len(out) {
+		case 0:
+			return id
+		case 1:
+			return id + " " + out[0]
+		}
+		return fmt.Sprintf("%s (%s)", id, strings.Join(out, ", "))
+	case reflect.Struct:
+		fields := make([]string, t.NumField())
+		for i := range fields {
+			field := t.Field(i)
+			name := field.Name
+			if !field.IsExported() {
+				name = field.PkgPath + "." + name
+			}
+			if field.Anonymous {
+				name = "embedded " + name
+			}
+			fields[i] = name + " " + typeID(field.Type)
+			if field.Tag != "" {
+				fields[i] += fmt.Sprintf(" %q", field.Tag)
+			}
+		}
+		return fmt.Sprintf("struct { %s }", strings.Join(fields, "; "))
+	case reflect.Interface:
+		methods := make([]string, t.NumMethod())
+		for i := range methods {
+			method := t.Method(i)
+			name := method.Name
+			if !method.IsExported() {
+				name = method.PkgPath + "." + name
+			}
+			methods[i] = name + strings.TrimPrefix(typeID(method.Type), "func")
+		}
+		return fmt.Sprintf("interface { %s }", strings.Join(methods, "; "))
+	}
+	return t.String()
+}
+
+// Adds a static service to the global container. A static service is only created once and lives for the life of the application.
 // Uses the specification (interface or struct) to inject an implementation into the IoC container
-func AddStaticService[T interface{}](spec T, impl any) error {
-	return addService(spec, impl, core.StaticLifetime)
+func AddStaticService[T interface{}](spec T, impl any, opts ...ServiceOption) error {
+	return GetRoids().AddStaticService(spec, impl, opts...)
 }
 
-// Adds a transient service to the container. A transient service is newly instantiated for each use.
-func AddTransientService[T interface{}](spec T, impl any) error {
-	return addService(spec, impl, core.TransientLifetime)
+// Adds a transient service to the global container. A transient service is newly instantiated for each use.
+func AddTransientService[T interface{}](spec T, impl any, opts ...ServiceOption) error {
+	return GetRoids().AddTransientService(spec, impl, opts...)
+}
+
+// Adds a scoped service to the global container. A scoped service is created once per scope and disposed with it.
+func AddScopedService[T interface{}](spec T, impl any, opts ...ServiceOption) error {
+	return GetRoids().AddScopedService(spec, impl, opts...)
 }
 
-// Gets an implementation of a service based on an specification from the container.
+// Gets an implementation of a service based on an specification from the global container.
 func Inject[T interface{}]() T {
-	c := GetRoids()
+	return InjectFrom[T](GetRoids())
+}
+
+// Gets an implementation of a service based on an specification from the global container.
+// Returns an error instead of panicking when the service cannot be constructed.
+func InjectE[T interface{}]() (T, error) {
+	return InjectFromE[T](GetRoids())
+}
+
+// Gets an implementation of a service based on an specification from the global container.
+// Returns false when the service is not registered, not built or cannot be constructed.
+func TryInject[T interface{}]() (T, bool) {
+	return TryInjectFrom[T](GetRoids())
+}
+
+// Adds a static service to the container. A static service is only created once and lives for the life of the container.
+// Uses the specification (interface or struct) to inject an implementation into the IoC container
+func (c *RoidsContainer) AddStaticService(spec any, impl any, opts ...ServiceOption) error {
+	return c.addService("", spec, impl, core.StaticLifetime, opts)
+}
+
+// Adds a transient service to the container. A transient service is newly instantiated for each use.
+func (c *RoidsContainer) AddTransientService(spec any, impl any, opts ...ServiceOption) error {
+	return c.addService("", spec, impl, core.TransientLifetime, opts)
+}
+
+// Adds a scoped service to the container. A scoped service is created once per scope and disposed with it.
+// Scoped services can only be injected from a `Scope`.
+func (c *RoidsContainer) AddScopedService(spec any, impl any, opts ...ServiceOption) error {
+	return c.addService("", spec, impl, core.ScopedLifetime, opts)
+}
+
+// Gets an implementation of a service based on an specification from the provided container or scope.
+func InjectFrom[T interface{}](r Resolver) T {
+	impl, err := InjectFromE[T](r)
+	if err != nil {
+		panic(err)
+	}
+	return impl
+}
+
+// Gets an implementation of a service based on an specification from the provided container or scope.
+// Returns an error instead of panicking when the service cannot be constructed.
+func InjectFromE[T interface{}](r Resolver) (T, error) {
+	return injectKeyed[T](r, "")
+}
+
+// Gets the implementation registered with the key from the provided container or scope.
+func injectKeyed[T interface{}](r Resolver, key string) (T, error) {
+	c, scope := r.container(), r.scope()
 
 	// service definition
 	specType := reflect.TypeOf(new(T)).Elem()
 
 	// Implementation of service
-	service := c.servicesGraph.getServiceByType(specType)
 	var impl T
-	if service.lifetimeType == core.StaticLifetime {
-		impl = (*(service.instance)).(T)
-		return impl
+	service := c.servicesGraph.getServiceByKey(specType, key)
+	if service == nil {
+		return impl, core.NewServiceNotRegisteredError(specType)
 	}
-	dep := buildTransientDep(service)
-	impl = (*dep).(T)
-	return impl
+	dep, err := c.resolve(service, scope)
+	if err != nil {
+		return impl, err
+	}
+	// Injectors can return a nil interface, which is injected as the zero value.
+	impl, _ = (*dep).(T)
+	return impl, nil
 }
 
-// Generic add service definition function.
-func addService[T interface{}](spec T, impl any, lifeTime string) error {
+// Gets an implementation of a service based on an specification from the provided container or scope.
+// Returns false when the service is not registered, not built or cannot be constructed.
+func TryInjectFrom[T interface{}](r Resolver) (T, bool) {
+	impl, err := InjectFromE[T](r)
+	return impl, err == nil
+}
 
-	// Get Container
-	container := GetRoids()
+// Generic add service definition function.
+// Errors are recorded to be reported again by `Validate`.
+func (c *RoidsContainer) addService(key string, spec any, impl any, lifeTime string, opts []ServiceOption) (err error) {
+	specType := reflect.TypeOf(spec).Elem()
+	defer c.recordError(serviceKey{specType: specType, key: key}, &err)
 
 	// Check for argument errors
-	specType := reflect.TypeOf(spec).Elem()
-	if lifeTime != core.StaticLifetime && lifeTime != core.TransientLifetime {
+	if lifeTime != core.StaticLifetime && lifeTime != core.TransientLifetime && lifeTime != core.ScopedLifetime {
 		return core.NewInvalidLifetimeError(nil, specType)
 	}
 
+	// Struct injectors are functions with a parameter for each injected field.
+	if si, ok := impl.(*structInjector); ok {
+		if si.err != nil {
+			return core.NewInjectorSignatureError(si.err, specType)
+		}
+		impl = si.injector
+		opts = append(si.options(), opts...)
+	}
+
 	if reflect.ValueOf(impl).Kind() != reflect.Func {
 		return core.NewInjectorError(specType)
 	}
 
 	ftype := reflect.TypeOf(impl)
+	if err := checkInjectorOutputs(ftype); err != nil {
+		return core.NewInjectorSignatureError(err, specType)
+	}
 	implType := ftype.Out(0)
-	if !implType.Implements(specType) {
-		return core.NewServiceError(specType, implType.Elem())
+	if !implType.AssignableTo(specType) {
+		return core.NewServiceError(specType, implType)
+	}
+
+	options := newServiceOptions(opts)
+	for index := range options.paramKeys {
+		if index < 0 || index >= ftype.NumIn() {
+			return core.NewInjectorSignatureError(fmt.Errorf("no parameter at index %d to key", index), specType)
+		}
+	}
+
+	// Group members are separate vertices, so they need a distinct key.
+	var group *Service
+	if options.group {
+		group = c.servicesGraph.getOrAddGroup(specType)
+		if key == "" {
+			key = fmt.Sprintf("group#%d", len(group.members))
+		}
 	}
 
 	// Add vertex for the service being added
-	srcService := &Service{Injector: impl, lifetimeType: lifeTime, SpecType: specType}
-	err := container.servicesGraph.addVertex(srcService)
-	if err != nil {
+	srcService := &Service{Injector: impl, lifetimeType: lifeTime, SpecType: specType, Key: key}
+	if err := c.servicesGraph.addVertex(srcService); err != nil {
 		// It means we added a vertex for this service before via a constructor.
 		// SO we must lookup the id based on the service type.
-		service := container.servicesGraph.getServiceByType(specType)
+		service := c.servicesGraph.getServiceByKey(specType, key)
 		service.implType = implType
 		service.Injector = impl
 		service.lifetimeType = lifeTime
 		service.SpecType = specType
+		// A placeholder group without members is replaced by the registered slice.
+		service.isGroup = len(service.members) > 0
 		srcService = service
 	}
+	srcService.lazy = options.lazy
+	srcService.onStart = options.onStart
+	srcService.onStop = options.onStop
+
+	if group != nil {
+		if err := c.servicesGraph.addGroupMember(group, srcService); err != nil {
+			return err
+		}
+	}
 
 	// Get all dependencies in injector
+	srcService.params = make([]*dependency, ftype.NumIn())
 	for i := 0; i < ftype.NumIn(); i++ {
-		field := ftype.In(i)
-		// Add vertex for dependency
-		depService := container.servicesGraph.getServiceByType(field)
-		if depService == nil {
-			// Ignore the error as service = nil meaning we should not get an error adding vertex.
-			depService = &Service{SpecType: field}
-			_ = container.servicesGraph.addVertex(depService)
-			err = container.servicesGraph.addEdge(srcService, depService)
-		} else {
-			err = container.servicesGraph.addEdge(srcService, depService)
+		if options.groupParams[i] {
+			c.servicesGraph.getOrAddGroup(ftype.In(i).Elem())
 		}
+		srcService.params[i], err = c.addParam(srcService, ftype.In(i), options.paramKeys[i], options.optionalParams[i])
 		if err != nil {
 			return err
 		}
 	}
+
+	if isObject(implType, outType) {
+		if err := c.addResults(srcService, implType); err != nil {
+			return err
+		}
+	}
+	for _, aliasType := range options.as {
+		if err := c.addAlias(srcService, implType, aliasType); err != nil {
+			return err
+		}
+	}
+	if errs := c.checkCaptives(c.getCaptivesOf(srcService)); len(errs) > 0 {
+		return errs[0]
+	}
 	return nil
 }
 
-// Add a custom configuration file.
+// Adds the dependency of an injector parameter of the type to the service.
+// Parameter objects embedding `In` add a dependency for each of their fields.
+func (c *RoidsContainer) addParam(srcService *Service, paramType reflect.Type, key string, optional bool) (*dependency, error) {
+	if isObject(paramType, inType) {
+		fields, err := getObjectFields(paramType, inType)
+		if err != nil {
+			return nil, core.NewInjectorSignatureError(err, srcService.SpecType)
+		}
+		param := &dependency{object: paramType, fields: make([]*dependency, len(fields))}
+		for i, field := range fields {
+			if field.group {
+				// Fields tagged `group` receive the group even when it has no members.
+				c.servicesGraph.getOrAddGroup(paramType.Field(field.index).Type.Elem())
+			}
+			fieldParam, err := c.addParam(srcService, paramType.Field(field.index).Type, field.key, field.optional)
+			if err != nil {
+				return nil, err
+			}
+			fieldParam.index = field.index
+			param.fields[i] = fieldParam
+		}
+		return param, nil
+	}
+
+	param, depType, kind := newDependency(paramType, optional)
+	depService, err := c.addDependency(srcService, depType, key, kind)
+	if err != nil {
+		return nil, err
+	}
+	param.service = depService
+	if kind == deferredEdge {
+		param.dependant = srcService
+	}
+	return param, nil
+}
+
+// Adds an edge of the kind from the service to the dependency of the type and key.
+// Adds a placeholder vertex for the dependency if it has not been registered yet.
+// Several parameters can share the edge to the same dependency.
+func (c *RoidsContainer) addDependency(srcService *Service, depType reflect.Type, depKey string, kind edgeKind) (*Service, error) {
+	depService := c.servicesGraph.getServiceByKey(depType, depKey)
+	if depService == nil {
+		// Ignore the error as service = nil meaning we should not get an error adding vertex.
+		// Unkeyed slices of interfaces are groups, which are empty until members are added.
+		depService = &Service{SpecType: depType, Key: depKey, isGroup: depKey == "" && isGroupType(depType)}
+		_ = c.servicesGraph.addVertex(depService)
+	}
+	if existing, ok := c.servicesGraph.getEdgeKind(srcService, depService); ok && (existing != deferredEdge || kind == deferredEdge) {
+		// Deferred edges are replaced by edges ordering construction.
+		return depService, nil
+	}
+	if err := c.servicesGraph.addEdgeKind(srcService, depService, kind); err != nil {
+		return nil, err
+	}
+	return depService, nil
+}
+
+// Type of the error interface, used to detect injectors that can fail.
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// Checks that an injector returns the service, optionally followed by an error.
+func checkInjectorOutputs(ftype reflect.Type) error {
+	if ftype.NumOut() == 1 || (ftype.NumOut() == 2 && ftype.Out(1) == errorType) {
+		return nil
+	}
+	if hasMultipleResults(ftype) {
+		return fmt.Errorf("%s returns several services, add it with Provide", ftype)
+	}
+	return fmt.Errorf("%s must return the service and an optional error", ftype)
+}
+
+// Add a custom configuration file to the global container.
 // Default roids.settings.json file.
 func AddConfigurationBuilder[T any](filePath string, cfgType core.ConfigType) error {
+	return AddConfigurationBuilderTo[T](GetRoids(), filePath, cfgType)
+}
+
+// Add Custom Configuration to the global container.
+// configuraitonRead expects a slice of bytes encoded as json or yaml depending on the config type.
+func AddCustomConfiguration[T any](configurationRead func() ([]byte, error), cfgType core.ConfigType) error {
+	return AddCustomConfigurationTo[T](GetRoids(), configurationRead, cfgType)
+}
+
+// Add a custom configuration file to the provided container.
+func AddConfigurationBuilderTo[T any](c *RoidsContainer, filePath string, cfgType core.ConfigType) error {
 	// Read file
 	readFile := func() ([]byte, error) {
 		return os.ReadFile(filePath)
 	}
-	return AddCustomConfiguration[T](readFile, cfgType)
+	return AddCustomConfigurationTo[T](c, readFile, cfgType)
 }
 
-// Add Custom Configuration
+// Add Custom Configuration to the provided container.
 // configuraitonRead expects a slice of bytes encoded as json or yaml depending on the config type.
-func AddCustomConfiguration[T any](configurationRead func() ([]byte, error), cfgType core.ConfigType) error {
+func AddCustomConfigurationTo[T any](c *RoidsContainer, configurationRead func() ([]byte, error), cfgType core.ConfigType) error {
 	setFile, err := configurationRead()
 	if err != nil {
 		return err
@@ -169,9 +652,9 @@ func AddCustomConfiguration[T any](configurationRead func() ([]byte, error), cfg
 	}
 
 	// add the service
-	err = addService(config.Create[config.IConfiguration[T]](), func() *config.RoidsConfiguration[T] {
+	err = c.addService("", config.Create[config.IConfiguration[T]](), func() *config.RoidsConfiguration[T] {
 		return &configFile
-	}, core.StaticLifetime)
+	}, core.StaticLifetime, nil)
 
 	if err != nil {
 		return err
@@ -4,13 +4,15 @@
  */
 
 // Package containing custom dependency container for dependency injection.
-// There is only ever one container and it can be used globally to access all the dependencies.
+// A global container can be used to access all the dependencies, and independent
+// containers can be created with `NewContainer`.
 package roids
 
 import (
-	"container/list"
 	"errors"
+	"fmt"
 	"reflect"
+	"sync"
 
 	"github.com/ShounakA/roids/col"
 	"github.com/ShounakA/roids/core"
@@ -19,14 +21,29 @@ import (
 type (
 	serviceGraph struct {
 		dag *core.AcyclicGraph
+		// Kind of every edge, by source service ID and then dependency ID.
+		edges   map[string]map[string]edgeKind
+		muEdges sync.RWMutex
+		// Vertex ID of every service, by specification type and key.
+		// Types with the same readable ID, like function-local types of the same name, get distinct vertex IDs.
+		ids   map[serviceKey]string
+		muIds sync.RWMutex
 	}
 
+	// Identity of a service: its specification type and key.
+	serviceKey struct {
+		specType reflect.Type
+		key      string
+	}
+
+	// Kind of relationship an edge represents between two services.
+	edgeKind int
+
 	// Dependency visitor. It keeps track of the nodes visited into a stack,
 	// so that we can instantiate leaf deps by popping them out.
-	depVisiter struct {
-		// History of the dependent services visited.
-		Hist   col.IStack[string]
-		HistV2 *list.List
+	// It does not update the services, so it is safe to use while services are being injected concurrently.
+	histVisiter struct {
+		Hist col.IStack[string]
 	}
 
 	// Struct to perform a lookup from the search type.
@@ -37,10 +54,26 @@ type (
 	}
 )
 
+const (
+	// The source service is constructed from the dependency.
+	dependencyEdge edgeKind = iota
+	// The source group collects the dependency as one of its members.
+	groupEdge
+	// The source service resolves the dependency on demand, through a `Provider` or `Lazy`.
+	factoryEdge
+	// The source service is constructed from the dependency if it is registered, and without it otherwise.
+	optionalEdge
+	// The source service resolves the dependency on first use, through a `Deferred`.
+	// Deferred edges are not added to the DAG, so they can form cycles and do not order construction.
+	deferredEdge
+)
+
 // Create a new service graph, with custom pointer functions.
 func newServiceGraph(d2 *core.AcyclicGraph) *serviceGraph {
 	return &serviceGraph{
-		dag: d2,
+		dag:   d2,
+		edges: make(map[string]map[string]edgeKind),
+		ids:   make(map[serviceKey]string),
 	}
 }
 
@@ -50,17 +83,22 @@ func (graph *serviceGraph) Print() {
 
 // Gets the order of instantiation, by traversing the graph breadth-first
 func (graph *serviceGraph) getInstantiationOrder() col.IStack[string] {
-	v := depVisiter{Hist: col.NewStack[string](nil), HistV2: list.New()}
+	v := histVisiter{Hist: col.NewStack[string](nil)}
 	graph.dag.TraverseTopological(&v)
-	// println(v.Hist.String())
 	v.Hist.Reverse()
-	// println(v.Hist.String())
 	return v.Hist
 }
 
+// Gets the layers of instantiation. Services in a layer only depend on services in earlier layers.
+func (graph *serviceGraph) getInstantiationLayers() [][]string {
+	// Edges are only added when they do not create a cycle.
+	layers, _ := graph.dag.TopologicalLayers()
+	return layers
+}
+
 // Gets the order of instantiation of the , by traversing the graph breadth-first
 func (graph *serviceGraph) getServiceOrderById(id string) col.IStack[string] {
-	v := depVisiter{Hist: col.NewStack[string](nil), HistV2: list.New()}
+	v := histVisiter{Hist: col.NewStack[string](nil)}
 	graph.dag.TraverseTopologicalTo(id, &v)
 	v.Hist.Reverse()
 	return v.Hist
@@ -68,12 +106,19 @@ func (graph *serviceGraph) getServiceOrderById(id string) col.IStack[string] {
 
 // Gets the Service struct from the graph by the interface type provided.
 func (graph *serviceGraph) getServiceByType(specType reflect.Type) *Service {
-	tmpService := Service{SpecType: specType}
-	if node, err := graph.dag.GetVertex(tmpService.ID()); err != nil {
+	return graph.getServiceByKey(specType, "")
+}
+
+// Gets the Service struct from the graph by the interface type and key provided.
+func (graph *serviceGraph) getServiceByKey(specType reflect.Type, key string) *Service {
+	graph.muIds.RLock()
+	id, ok := graph.ids[serviceKey{specType: specType, key: key}]
+	graph.muIds.RUnlock()
+	if !ok {
 		return nil
-	} else {
-		return node.Value().(*Service)
 	}
+	service, _ := graph.getVertex(id)
+	return service
 }
 
 // Get a specific dependency node based on the id provided
@@ -91,20 +136,47 @@ func (graph *serviceGraph) addVertex(service *Service) error {
 	if service == nil {
 		return errors.New("Cannot add nil service")
 	}
-	id, err := graph.dag.AddVertex(service)
-	service.Id = id
-	if err != nil {
+	graph.muIds.Lock()
+	defer graph.muIds.Unlock()
+	key := serviceKey{specType: service.SpecType, key: service.Key}
+	if _, exists := graph.ids[key]; exists {
+		service.Id = ""
+		return errors.New("Node with same value already exists in graph")
+	}
+	// Distinct types with the same readable ID are numbered.
+	label := service.label()
+	service.Id = label
+	for n := 2; graph.hasVertex(service.Id); n++ {
+		service.Id = fmt.Sprintf("%s~%d", label, n)
+	}
+	if _, err := graph.dag.AddVertex(service); err != nil {
+		service.Id = ""
 		return err
 	}
+	graph.ids[key] = service.Id
 	return nil
 }
 
+// True if the graph has a vertex with the ID.
+func (graph *serviceGraph) hasVertex(id string) bool {
+	_, err := graph.dag.GetVertex(id)
+	return err == nil
+}
+
 // Adds a services edge. This edge represents what the srcService depends on.
 func (graph *serviceGraph) addEdge(srcService *Service, depService *Service) error {
+	return graph.addEdgeKind(srcService, depService, dependencyEdge)
+}
+
+// Adds a services edge of the specified kind.
+func (graph *serviceGraph) addEdgeKind(srcService *Service, depService *Service, kind edgeKind) error {
 	if srcService == nil || depService == nil {
 		return errors.New("Cannot add edge to or from nil")
 	}
-	err := graph.dag.AddEdge(depService.Id, srcService.Id)
+	var err error
+	if kind != deferredEdge {
+		err = graph.dag.AddEdge(depService.Id, srcService.Id)
+	}
 	if err != nil {
 		switch e := err.(type) {
 		case *core.EdgeCycleError:
@@ -115,17 +187,53 @@ func (graph *serviceGraph) addEdge(srcService *Service, depService *Service) err
 			return core.NewUnknownError(e)
 		}
 	}
+	graph.muEdges.Lock()
+	defer graph.muEdges.Unlock()
+	if graph.edges[srcService.Id] == nil {
+		graph.edges[srcService.Id] = make(map[string]edgeKind)
+	}
+	graph.edges[srcService.Id][depService.Id] = kind
 	return nil
 }
 
+// Gets the kind of the edge from srcService to depService. False if there is no such edge.
+func (graph *serviceGraph) getEdgeKind(srcService *Service, depService *Service) (edgeKind, bool) {
+	graph.muEdges.RLock()
+	defer graph.muEdges.RUnlock()
+	kind, ok := graph.edges[srcService.Id][depService.Id]
+	return kind, ok
+}
+
+// True if there is an edge of any kind from srcService to depService.
+func (graph *serviceGraph) hasEdge(srcService *Service, depService *Service) bool {
+	_, ok := graph.getEdgeKind(srcService, depService)
+	return ok
+}
+
+// True if constructing the service requires constructing the dependency, directly or through other services.
+// A service requires itself.
+func (graph *serviceGraph) dependsOn(service *Service, depService *Service) bool {
+	order := graph.getServiceOrderById(service.Id)
+	for order.GetSize() > 0 {
+		if *order.Pop() == depService.Id {
+			return true
+		}
+	}
+	return false
+}
+
 // Function to clear the services graph
 func (graph *serviceGraph) clearGraph() {
 	graph.dag = core.NewGraph()
+	graph.muEdges.Lock()
+	defer graph.muEdges.Unlock()
+	graph.edges = make(map[string]map[string]edgeKind)
+	graph.muIds.Lock()
+	defer graph.muIds.Unlock()
+	graph.ids = make(map[serviceKey]string)
 }
 
-func (pv *depVisiter) Do(v *core.Traverser) {
+func (hv *histVisiter) Do(v *core.Traverser) {
 	service := v.GetVertex().Value().(*Service)
-	pv.Hist.Push(service.Id)
-	service.isLeaf = v.GetVertex().IsLeaf()
-	service.isRoot = v.GetVertex().IsRoot()
+	hv.Hist.Push(service.Id)
 }
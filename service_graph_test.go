@@ -243,3 +243,60 @@ func TestGetVertex(t *testing.T) {
 		t.Errorf("Created vertex should be the same as the one created. %s", err.Error())
 	}
 }
+
+func TestGetServiceByKey(t *testing.T) {
+	tearDown := setupTest(t)
+	defer tearDown(t)
+	expectedOrder := 3
+
+	testSpec := reflect.TypeOf(5)
+	defaultService := &Service{SpecType: testSpec}
+	primaryService := &Service{SpecType: testSpec, Key: "primary"}
+	replicaService := &Service{SpecType: testSpec, Key: "replica"}
+
+	for _, service := range []*Service{defaultService, primaryService, replicaService} {
+		if err := graph.addVertex(service); err != nil {
+			t.Errorf("Should add keyed vertex! %s", err.Error())
+		}
+	}
+	if graph.dag.GetOrder() != expectedOrder {
+		t.Errorf("Keyed services should be separate vertices.")
+	}
+	if graph.getServiceByKey(testSpec, "replica") != replicaService {
+		t.Errorf("Should get the service by key.")
+	}
+	if graph.getServiceByType(testSpec) != defaultService {
+		t.Errorf("Should get the default service without a key.")
+	}
+}
+
+func TestAddEdgeKind(t *testing.T) {
+	tearDown := setupTest(t)
+	defer tearDown(t)
+
+	memberService := &Service{SpecType: reflect.TypeOf(5), Key: "group#0"}
+	groupService := &Service{SpecType: reflect.TypeOf([]int{}), isGroup: true}
+	consumerService := &Service{SpecType: reflect.TypeOf(int64(5))}
+	for _, service := range []*Service{memberService, groupService, consumerService} {
+		if err := graph.addVertex(service); err != nil {
+			t.Errorf("Should add vertex! %s", err.Error())
+		}
+	}
+
+	if err := graph.addEdgeKind(groupService, memberService, groupEdge); err != nil {
+		t.Errorf("Should add group edge! %s", err.Error())
+	}
+	if err := graph.addEdge(consumerService, groupService); err != nil {
+		t.Errorf("Should add edge! %s", err.Error())
+	}
+
+	if kind, ok := graph.getEdgeKind(groupService, memberService); !ok || kind != groupEdge {
+		t.Errorf("Should record the group edge kind.")
+	}
+	if kind, ok := graph.getEdgeKind(consumerService, groupService); !ok || kind != dependencyEdge {
+		t.Errorf("Should record the dependency edge kind.")
+	}
+	if _, ok := graph.getEdgeKind(consumerService, memberService); ok {
+		t.Errorf("Should not record edges that were not added.")
+	}
+}
@@ -0,0 +1,100 @@
+package roids
+
+import (
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type Writer interface {
+	Write(p []byte) (n int, err error)
+}
+
+func TestTypeID(t *testing.T) {
+	cases := []struct {
+		spec any
+		id   string
+	}{
+		{new(io.Writer), "io.Writer"},
+		{new(Writer), "github.com/ShounakA/roids.Writer"},
+		{new(func() time.Time), "func() time.Time"},
+		{new([]byte), "[]uint8"},
+		{new(map[string]int), "map[string]int"},
+		{new(func(string, ...int) (*Writer, error)), "func(string, ...int) (*github.com/ShounakA/roids.Writer, error)"},
+		{new(Provider[io.Writer]), "github.com/ShounakA/roids.Provider[io.Writer]"},
+		{new(struct {
+			Out io.Writer `json:"out"`
+		}), "struct { Out io.Writer \"json:\\\"out\\\"\" }"},
+	}
+	for _, c := range cases {
+		if id := typeID(reflect.TypeOf(c.spec).Elem()); id != c.id {
+			t.Errorf("Expected %s, got %s", c.id, id)
+		}
+	}
+}
+
+func TestServiceID(t *testing.T) {
+	service := &Service{SpecType: reflect.TypeOf(new(io.Writer)).Elem(), Key: "stdout"}
+	if service.ID() != "io.Writer#stdout" {
+		t.Errorf("Expected the ID to include the key, got %s", service.ID())
+	}
+}
+
+func TestAddVertex_UnnamedTypes(t *testing.T) {
+	tearDown := setupTest(t)
+	defer tearDown(t)
+
+	specs := []reflect.Type{
+		reflect.TypeOf(new(func() time.Time)).Elem(),
+		reflect.TypeOf(new([]byte)).Elem(),
+		reflect.TypeOf(new(map[string]int)).Elem(),
+	}
+	for _, spec := range specs {
+		if err := graph.addVertex(&Service{SpecType: spec}); err != nil {
+			t.Errorf("Unnamed types should not collide, got %v for %s", err, spec)
+		}
+	}
+	for _, spec := range specs {
+		if service := graph.getServiceByType(spec); service == nil || service.SpecType != spec {
+			t.Errorf("Should get the service of %s", spec)
+		}
+	}
+}
+
+// Gets a function-local type named impl.
+func firstLocalType() reflect.Type {
+	type impl struct{ first bool }
+	return reflect.TypeOf(impl{})
+}
+
+// Gets another function-local type named impl, with the same readable ID.
+func secondLocalType() reflect.Type {
+	type impl struct{ second bool }
+	return reflect.TypeOf(impl{})
+}
+
+func TestAddVertex_LocalTypes(t *testing.T) {
+	tearDown := setupTest(t)
+	defer tearDown(t)
+
+	first, second := firstLocalType(), secondLocalType()
+	if typeID(first) != typeID(second) {
+		t.Fatalf("Expected the local types to share a readable ID, got %s and %s", typeID(first), typeID(second))
+	}
+	for _, spec := range []reflect.Type{first, second} {
+		if err := graph.addVertex(&Service{SpecType: spec, Key: "local"}); err != nil {
+			t.Errorf("Local types of the same name should not collide, got %v", err)
+		}
+	}
+	if err := graph.addVertex(&Service{SpecType: first, Key: "local"}); err == nil {
+		t.Error("Should not add the same type and key twice.")
+	}
+	firstService, secondService := graph.getServiceByKey(first, "local"), graph.getServiceByKey(second, "local")
+	if firstService == nil || secondService == nil || firstService.SpecType != first || secondService.SpecType != second {
+		t.Fatal("Should get the service of each local type.")
+	}
+	if firstService.Id == secondService.Id {
+		t.Errorf("Local types should have distinct vertex IDs, got %s", firstService.Id)
+	}
+}
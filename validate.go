@@ -0,0 +1,108 @@
+package roids
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/ShounakA/roids/core"
+)
+
+// Checks the global container without constructing any service. See `RoidsContainer.Validate`.
+func Validate() error {
+	return GetRoids().Validate()
+}
+
+// Checks the whole container without constructing any service, so it can run in a unit test.
+// Reports every error returned when adding services, unless they were added again successfully,
+// every dependency that is not registered with the path of services requiring it,
+// and captive dependencies rejected by the `CaptivePolicy`.
+// The errors are joined, and are nil if the container is valid.
+func (c *RoidsContainer) Validate() error {
+	var errs []error
+	for _, e := range c.registrationErrs {
+		// Captive dependencies are checked again with the whole graph below.
+		if _, ok := e.err.(*core.LifetimeMismatchError); !ok {
+			errs = append(errs, e.err)
+		}
+	}
+	for _, layer := range c.servicesGraph.getInstantiationLayers() {
+		for _, id := range layer {
+			service, _ := c.servicesGraph.getVertex(id)
+			if !service.registered() {
+				continue
+			}
+			for _, param := range service.allParams() {
+				if !param.optional && !param.service.registered() {
+					path := c.servicesGraph.getDependantPath(service)
+					errs = append(errs, core.NewMissingDependencyError(param.service.SpecType, path))
+				}
+			}
+			if service.lifetimeType == core.StaticLifetime {
+				errs = append(errs, c.checkCaptives(getCaptives(service))...)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Error returned when adding a service or one of its decorators.
+type registrationError struct {
+	service   serviceKey
+	decorator bool
+	err       error
+}
+
+// Records the error returned when adding the service, if any.
+// Registering the service again successfully drops the error recorded for it before.
+func (c *RoidsContainer) recordError(service serviceKey, err *error) {
+	c.record(registrationError{service: service, err: *err})
+}
+
+// Records the error returned when decorating the service, if any.
+// Decorating the service successfully drops the error recorded for its decorators before.
+func (c *RoidsContainer) recordDecoratorError(service serviceKey, err *error) {
+	c.record(registrationError{service: service, decorator: true, err: *err})
+}
+
+// Replaces the error recorded for the same registration, keeping the others in the order they were recorded.
+func (c *RoidsContainer) record(e registrationError) {
+	errs := make([]registrationError, 0, len(c.registrationErrs)+1)
+	for _, recorded := range c.registrationErrs {
+		if recorded.service != e.service || recorded.decorator != e.decorator {
+			errs = append(errs, recorded)
+		}
+	}
+	if e.err != nil {
+		errs = append(errs, e)
+	}
+	c.registrationErrs = errs
+}
+
+// Gets the path of services from the outermost dependant of the service to the service itself.
+// Follows the first dependant by ID when a service has several.
+func (graph *serviceGraph) getDependantPath(service *Service) []string {
+	path := []string{service.String()}
+	for dependants := graph.getDependants(service); len(dependants) > 0; dependants = graph.getDependants(dependants[0]) {
+		path = append([]string{dependants[0].String()}, path...)
+	}
+	return path
+}
+
+// Gets the services with an edge to the service, sorted by ID.
+// Deferred edges are skipped, as they can form cycles.
+func (graph *serviceGraph) getDependants(service *Service) []*Service {
+	graph.muEdges.RLock()
+	var ids []string
+	for srcId, deps := range graph.edges {
+		if kind, ok := deps[service.Id]; ok && kind != deferredEdge {
+			ids = append(ids, srcId)
+		}
+	}
+	graph.muEdges.RUnlock()
+	sort.Strings(ids)
+	dependants := make([]*Service, len(ids))
+	for i, id := range ids {
+		dependants[i], _ = graph.getVertex(id)
+	}
+	return dependants
+}
@@ -0,0 +1,119 @@
+package roids_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ShounakA/roids"
+	"github.com/ShounakA/roids/core"
+)
+
+func unwrapJoined(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return nil
+}
+
+func TestValidate(t *testing.T) {
+	c := roids.NewContainer()
+	constructed := false
+	_ = c.AddStaticService(new(IDbProvider), func() *SqliteProvider {
+		constructed = true
+		return NewSqliteProvider()
+	})
+	_ = c.AddStaticService(new(ICache), NewCache)
+	_ = c.AddTransientService(new(ITodoRepository), NewTodoRepository)
+
+	if err := c.Validate(); err != nil {
+		t.Error("Should validate a complete container.", err.Error())
+	}
+	if constructed {
+		t.Error("Validate should not construct services.")
+	}
+}
+
+func TestValidate_MissingDependencies(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddStaticService(new(myInterface), func(repo ITodoRepository) *shape { return &shape{} })
+	_ = c.AddTransientService(new(ITodoRepository), NewTodoRepository)
+	_ = c.AddStaticService(new(iRepoFactory), newRepoFactory, roids.WithParamKey(0, "missing"))
+	// Only the replica is missing, the cache is optional.
+	_ = c.AddKeyedStaticService("handler", new(myInterface), roids.Struct[todoHandler]())
+
+	errs := unwrapJoined(c.Validate())
+	if len(errs) != 5 {
+		t.Fatalf("Expected an error for each missing dependency, got %v", errs)
+	}
+	var db *core.ServiceNotRegisteredError
+	for _, err := range errs {
+		var missing *core.ServiceNotRegisteredError
+		if !errors.As(err, &missing) {
+			t.Errorf("Expected ServiceNotRegisteredError, got %v", err)
+		} else if missing.SpecType.Name() == "IDbProvider" && len(missing.Path) == 2 {
+			db = missing
+		}
+	}
+	if db == nil || !strings.Contains(db.Path[0], "myInterface") || !strings.Contains(db.Path[1], "ITodoRepository") {
+		t.Errorf("Should report the path of services requiring the dependency, got %v", errs)
+	}
+}
+
+func TestValidate_RegistrationErrors(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddStaticService(new(IDbProvider), "not an injector")
+	_ = c.AddStaticService(new(ICache), func() (*MyCache, *MyCache) { return nil, nil })
+
+	errs := unwrapJoined(c.Validate())
+	if len(errs) != 2 {
+		t.Fatalf("Expected the errors of both registrations, got %v", errs)
+	}
+	for _, err := range errs {
+		if _, ok := err.(*core.InjectorError); !ok {
+			t.Errorf("Expected InjectorError, got %v", err)
+		}
+	}
+}
+
+func TestValidate_RegisteredAgain(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddStaticService(new(IDbProvider), "not an injector")
+	_ = c.AddStaticService(new(ICache), "not an injector")
+	_ = roids.DecorateTo[ICache](c, withName("metrics"))
+	_ = c.AddStaticService(new(IDbProvider), NewSqliteProvider)
+
+	errs := unwrapJoined(c.Validate())
+	if len(errs) != 2 {
+		t.Fatalf("Expected only the errors of services not registered again, got %v", errs)
+	}
+	if _, ok := errs[0].(*core.InjectorError); !ok {
+		t.Errorf("Expected InjectorError, got %v", errs[0])
+	}
+	if _, ok := errs[1].(*core.ServiceNotRegisteredError); !ok {
+		t.Errorf("Expected ServiceNotRegisteredError, got %v", errs[1])
+	}
+
+	_ = c.AddStaticService(new(ICache), NewCache)
+	_ = roids.DecorateTo[ICache](c, func(inner ICache) ICache { return inner })
+	if err := c.Validate(); err != nil {
+		t.Error("Should not report errors of services registered again.", err.Error())
+	}
+}
+
+func TestValidate_StaticDependsOnScoped(t *testing.T) {
+	c := roids.NewContainer()
+	_ = c.AddStaticService(new(myInterface), func(repo ITodoRepository) *shape { return &shape{} })
+	_ = c.AddTransientService(new(ITodoRepository), NewTodoRepository)
+	_ = c.AddScopedService(new(IDbProvider), NewSqliteProvider)
+	_ = c.AddStaticService(new(ICache), NewCache)
+
+	err := c.Validate()
+	var mismatch *core.LifetimeMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("Expected LifetimeMismatchError, got %v", err)
+	}
+	if mismatch.SpecType.Name() != "myInterface" || mismatch.DependencySpecType.Name() != "IDbProvider" {
+		t.Errorf("Should report the static and the scoped services, got %v", mismatch)
+	}
+}